@@ -0,0 +1,30 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	handlers "github.com/m4r4v/go-rest-api/handlers"
+	health "github.com/m4r4v/go-rest-api/health"
+	interfaces "github.com/m4r4v/go-rest-api/interfaces"
+)
+
+// capabilities tells generic clients which optional subsystems this
+// deployment has enabled
+func capabilities(w http.ResponseWriter, r *http.Request) {
+
+	handlers.WriteJSON(w, r, http.StatusOK, &interfaces.ICapabilities{
+		Status:          http.StatusOK,
+		APIVersions:     []string{strings.TrimPrefix(data.apiVersion, "/")},
+		Envelope:        handlers.Envelope(),
+		RequestSigning:  len(data.signingClients) > 0,
+		Deprecations:    len(data.deprecatedRoutes) > 0,
+		ConcurrencyCaps: len(data.routeConcurrency) > 0,
+		AdminListener:   data.adminPort != "",
+		RateLimiting:    data.rateLimit > 0,
+		FieldFiltering:  true,
+		HealthChecks:    health.Registry.Names(),
+		Storage:         "none",
+	})
+
+}
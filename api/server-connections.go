@@ -0,0 +1,131 @@
+package api
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	handlers "github.com/m4r4v/go-rest-api/handlers"
+	interfaces "github.com/m4r4v/go-rest-api/interfaces"
+)
+
+// ServerConnections counts the connections handled by the http.Server
+type ServerConnections struct {
+	accepted, rejected atomic.Int64
+
+	mu     sync.Mutex
+	states map[net.Conn]http.ConnState
+}
+
+var connections = &ServerConnections{
+	states: map[net.Conn]http.ConnState{},
+}
+
+// ConnState is used as http.Server.ConnState to follow every connection
+func (c *ServerConnections) ConnState(conn net.Conn, state http.ConnState) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch state {
+	case http.StateNew:
+		c.accepted.Add(1)
+		c.states[conn] = state
+	case http.StateClosed, http.StateHijacked:
+		delete(c.states, conn)
+	default:
+		c.states[conn] = state
+	}
+
+}
+
+// Open returns the number of open connections and how many of them are idle
+func (c *ServerConnections) Open() (open, idle int64) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, state := range c.states {
+		if state == http.StateIdle {
+			idle++
+		}
+	}
+
+	return int64(len(c.states)), idle
+
+}
+
+// Metrics serves the connection counters as JSON
+func (c *ServerConnections) Metrics(w http.ResponseWriter, r *http.Request) {
+
+	open, idle := c.Open()
+
+	handlers.WriteJSON(w, r, http.StatusOK, &interfaces.IConnectionMetrics{
+		Status:   http.StatusOK,
+		Accepted: c.accepted.Load(),
+		Rejected: c.rejected.Load(),
+		Active:   open - idle,
+		Idle:     idle,
+	})
+
+}
+
+// limitListener closes connections accepted beyond max open connections
+type limitListener struct {
+	net.Listener
+	slots       chan struct{}
+	connections *ServerConnections
+}
+
+func newLimitListener(l net.Listener, max int, connections *ServerConnections) net.Listener {
+
+	if max <= 0 {
+		return l
+	}
+
+	return &limitListener{
+		Listener:    l,
+		slots:       make(chan struct{}, max),
+		connections: connections,
+	}
+
+}
+
+func (l *limitListener) Accept() (net.Conn, error) {
+
+	for {
+
+		conn, err := l.Listener.Accept()
+
+		if err != nil {
+			return nil, err
+		}
+
+		select {
+		case l.slots <- struct{}{}:
+			return &limitConn{Conn: conn, release: func() { <-l.slots }}, nil
+		default:
+			l.connections.rejected.Add(1)
+			conn.Close()
+		}
+
+	}
+
+}
+
+type limitConn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+func (c *limitConn) Close() error {
+
+	err := c.Conn.Close()
+
+	c.once.Do(c.release)
+
+	return err
+
+}
@@ -1,5 +1,283 @@
 package api
 
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	clock "github.com/m4r4v/go-rest-api/clock"
+	handlers "github.com/m4r4v/go-rest-api/handlers"
+)
+
 type ServerData struct {
 	port, apiVersion string
+
+	// response envelope, "legacy" or "v2"
+	envelope string
+
+	// port for management routes such as /metrics, empty serves them on port
+	adminPort string
+
+	// http.Server tuning
+	readHeaderTimeout, readTimeout, writeTimeout, idleTimeout time.Duration
+	maxHeaderBytes                                            int
+	keepAlive                                                 bool
+
+	// largest request body accepted
+	maxBodyBytes int64
+
+	// maximum simultaneous connections, 0 means unlimited
+	maxConnections int
+
+	// partner client ids and secrets for HMAC signed requests
+	signingClients   map[string]string
+	signingTolerance time.Duration
+
+	// route templates answering with Deprecation / Sunset headers, when they
+	// were deprecated, and whether they answer 410 Gone after the sunset date
+	deprecatedRoutes map[string]time.Time
+	deprecatedSince  time.Time
+	deprecatedGone   bool
+
+	// simultaneous requests allowed per route template, with the size of and
+	// maximum wait in the queue of requests over the limit
+	routeConcurrency  map[string]int
+	routeQueueSize    int
+	routeQueueTimeout time.Duration
+
+	// requests a minute per caller on /v1, 0 disables rate limiting, callers are
+	// keyed by "ip" or by authenticated "client"
+	rateLimit           int
+	rateLimitBurst      int
+	rateLimitKey        string
+	rateLimitTrustProxy bool
+
+	// requests a minute per IP on /public/status, counted apart from /v1,
+	// 0 disables it
+	publicStatusRateLimit int
+
+	// Subresource Integrity hash of the Redoc script loaded by /docs, empty
+	// serves the page without it
+	docsIntegrity string
+
+	// time given to open connections to finish on shutdown
+	shutdownTimeout time.Duration
+}
+
+// newServerData returns the default server settings overridden by any
+// environment variable that is set
+func newServerData() *ServerData {
+
+	return &ServerData{
+		apiVersion:        "/v1",
+		port:              envString("PORT", "8080"),
+		adminPort:         envString("ADMIN_PORT", ""),
+		envelope:          envString("RESPONSE_ENVELOPE", "legacy"),
+		docsIntegrity:     envString("DOCS_REDOC_INTEGRITY", ""),
+		readHeaderTimeout: envDuration("SERVER_READ_HEADER_TIMEOUT", 5*time.Second),
+		readTimeout:       envDuration("SERVER_READ_TIMEOUT", 15*time.Second),
+		writeTimeout:      envDuration("SERVER_WRITE_TIMEOUT", 15*time.Second),
+		idleTimeout:       envDuration("SERVER_IDLE_TIMEOUT", 60*time.Second),
+		maxHeaderBytes:    envInt("SERVER_MAX_HEADER_BYTES", 1<<20),
+		keepAlive:         envBool("SERVER_KEEP_ALIVE", true),
+		maxBodyBytes:      int64(envInt("MAX_BODY_BYTES", 1<<20)),
+		maxConnections:    envInt("SERVER_MAX_CONNECTIONS", 0),
+		shutdownTimeout:   envDuration("SERVER_SHUTDOWN_TIMEOUT", 10*time.Second),
+		signingClients:    envPairs("SIGNING_CLIENTS"),
+		signingTolerance:  envPositiveDuration("SIGNING_TOLERANCE", handlers.DefaultSignatureTolerance),
+		deprecatedRoutes:  envDates("DEPRECATED_ROUTES"),
+		deprecatedSince:   envDate("DEPRECATED_SINCE", clock.Now()),
+		deprecatedGone:    envBool("DEPRECATED_ROUTES_GONE", false),
+		routeConcurrency:  envLimits("ROUTE_CONCURRENCY"),
+		routeQueueSize:    envInt("ROUTE_QUEUE_SIZE", 10),
+		routeQueueTimeout: envDuration("ROUTE_QUEUE_TIMEOUT", 5*time.Second),
+
+		rateLimit:           envInt("RATE_LIMIT_PER_MINUTE", 0),
+		rateLimitBurst:      envInt("RATE_LIMIT_BURST", 0),
+		rateLimitKey:        envString("RATE_LIMIT_KEY", "ip"),
+		rateLimitTrustProxy: envBool("RATE_LIMIT_TRUST_PROXY", false),
+
+		publicStatusRateLimit: envInt("PUBLIC_STATUS_RATE_LIMIT", 60),
+	}
+
+}
+
+func envString(key, fallback string) string {
+
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+
+	return fallback
+
+}
+
+func envInt(key string, fallback int) int {
+
+	value, ok := os.LookupEnv(key)
+
+	if !ok || value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.Atoi(value)
+
+	if err != nil || parsed < 0 {
+		log.Println("Invalid " + key + ", using default")
+		return fallback
+	}
+
+	return parsed
+
+}
+
+func envBool(key string, fallback bool) bool {
+
+	value, ok := os.LookupEnv(key)
+
+	if !ok || value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.ParseBool(value)
+
+	if err != nil {
+		log.Println("Invalid " + key + ", using default")
+		return fallback
+	}
+
+	return parsed
+
+}
+
+// envPairs parses "key:value,key:value" into a map
+func envPairs(key string) map[string]string {
+
+	pairs := map[string]string{}
+
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+
+		name, value, found := strings.Cut(strings.TrimSpace(pair), ":")
+
+		if !found || name == "" || value == "" {
+			continue
+		}
+
+		pairs[name] = value
+
+	}
+
+	return pairs
+
+}
+
+// envDates parses "key=2006-01-02,key=2006-01-02" into a map
+func envDates(key string) map[string]time.Time {
+
+	dates := map[string]time.Time{}
+
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+
+		name, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+
+		if !found || name == "" {
+			continue
+		}
+
+		date, err := time.Parse("2006-01-02", value)
+
+		if err != nil {
+			log.Println("Invalid " + key + " date for " + name + ", ignoring it")
+			continue
+		}
+
+		dates[name] = date
+
+	}
+
+	return dates
+
+}
+
+// envDate parses a 2006-01-02 date
+func envDate(key string, fallback time.Time) time.Time {
+
+	value, ok := os.LookupEnv(key)
+
+	if !ok || value == "" {
+		return fallback
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+
+	if err != nil {
+		log.Println("Invalid " + key + ", using default")
+		return fallback
+	}
+
+	return date
+
+}
+
+// envLimits parses "key=1,key=2" into a map of positive limits
+func envLimits(key string) map[string]int {
+
+	limits := map[string]int{}
+
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+
+		name, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+
+		if !found || name == "" {
+			continue
+		}
+
+		limit, err := strconv.Atoi(value)
+
+		if err != nil || limit < 1 {
+			log.Println("Invalid " + key + " limit for " + name + ", ignoring it")
+			continue
+		}
+
+		limits[name] = limit
+
+	}
+
+	return limits
+
+}
+
+// envPositiveDuration is envDuration for settings where zero is not a valid value
+func envPositiveDuration(key string, fallback time.Duration) time.Duration {
+
+	value := envDuration(key, fallback)
+
+	if value <= 0 {
+		log.Println("Invalid " + key + ", it must be greater than zero, using default")
+		return fallback
+	}
+
+	return value
+
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+
+	value, ok := os.LookupEnv(key)
+
+	if !ok || value == "" {
+		return fallback
+	}
+
+	parsed, err := time.ParseDuration(value)
+
+	if err != nil || parsed < 0 {
+		log.Println("Invalid " + key + ", using default")
+		return fallback
+	}
+
+	return parsed
+
 }
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// newServer applies the ServerData tuning to a server listening on port
+func newServer(port string, handler http.Handler) *http.Server {
+
+	server := &http.Server{
+		Addr:              ":" + port,
+		Handler:           handler,
+		ReadHeaderTimeout: data.readHeaderTimeout,
+		ReadTimeout:       data.readTimeout,
+		WriteTimeout:      data.writeTimeout,
+		IdleTimeout:       data.idleTimeout,
+		MaxHeaderBytes:    data.maxHeaderBytes,
+		ConnState:         connections.ConnState,
+	}
+
+	server.SetKeepAlivesEnabled(data.keepAlive)
+
+	return server
+
+}
+
+// serve starts every server and blocks until all of them are shut down
+func serve(servers []*http.Server) {
+
+	listeners := make([]net.Listener, len(servers))
+
+	// bind every port first so a taken port fails before anything is served
+	for i, server := range servers {
+
+		listener, err := net.Listen("tcp", server.Addr)
+
+		if err != nil {
+			log.Fatal("Server Start Error: " + err.Error())
+		}
+
+		listeners[i] = newLimitListener(listener, data.maxConnections, connections)
+
+	}
+
+	// drain open connections on SIGINT / SIGTERM
+	drained := make(chan struct{})
+
+	go shutdownOnSignal(servers, drained)
+
+	var wg sync.WaitGroup
+
+	for i, server := range servers {
+
+		wg.Add(1)
+
+		go func(server *http.Server, listener net.Listener) {
+
+			defer wg.Done()
+
+			// print text to let knoe the server is running
+			log.Println("Listenting on Port: " + server.Addr[1:])
+
+			// start server or log error
+			err := server.Serve(listener)
+
+			if err != http.ErrServerClosed {
+				log.Fatal("Server Start Error: " + err.Error())
+			}
+
+		}(server, listeners[i])
+
+	}
+
+	wg.Wait()
+
+	// wait for the drain report before returning
+	<-drained
+
+}
+
+// shutdownOnSignal stops accepting connections and waits for the open ones
+// to finish, logging how many were drained
+func shutdownOnSignal(servers []*http.Server, drained chan struct{}) {
+
+	defer close(drained)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	<-signals
+
+	open, idle := connections.Open()
+
+	log.Printf("Shutting down, draining %d connections (%d active, %d idle)", open, open-idle, idle)
+
+	ctx, cancel := context.WithTimeout(context.Background(), data.shutdownTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var failed bool
+	var mu sync.Mutex
+
+	for _, server := range servers {
+
+		wg.Add(1)
+
+		go func(server *http.Server) {
+
+			defer wg.Done()
+
+			if err := server.Shutdown(ctx); err != nil {
+
+				log.Println("Shutdown Error: " + err.Error())
+
+				mu.Lock()
+				failed = true
+				mu.Unlock()
+
+			}
+
+		}(server)
+
+	}
+
+	wg.Wait()
+
+	remaining, _ := connections.Open()
+
+	if failed {
+		log.Printf("Shutdown incomplete, %d connections still open", remaining)
+		return
+	}
+
+	log.Printf("Shutdown complete, %d connections drained", open-remaining)
+
+}
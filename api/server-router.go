@@ -1,27 +1,48 @@
 package api
 
 import (
-	"log"
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
 	handlers "github.com/m4r4v/go-rest-api/handlers"
+	interfaces "github.com/m4r4v/go-rest-api/interfaces"
+	openapi "github.com/m4r4v/go-rest-api/openapi"
 	resources "github.com/m4r4v/go-rest-api/resources"
+	version "github.com/m4r4v/go-rest-api/version"
 )
 
-var data = &ServerData{
-	apiVersion: "/v1",
-	port:       "8080",
-}
+var data = newServerData()
 
 func ServerRouter() {
 
+	router, admin := NewRouter()
+
+	servers := []*http.Server{newServer(data.port, router)}
+
+	if data.adminPort != "" {
+		servers = append(servers, newServer(data.adminPort, admin))
+	}
+
+	serve(servers)
+
+}
+
+// NewRouter registers every route, admin is the same router as the public one
+// unless an admin port is configured
+func NewRouter() (router, admin *mux.Router) {
+
+	// every handler writes through handlers.WriteJSON in this envelope
+	handlers.SetEnvelope(data.envelope)
+
 	// New Router Instance
-	router := mux.NewRouter().StrictSlash(true)
+	router = mux.NewRouter().StrictSlash(true)
+
+	// caller's trace id in the request context and logs of every route
+	router.Use(handlers.HandlerTrace)
 
-	// Set Headers to accept only JSON requests
-	// TODO
-	// Show Content Type error message in JSON
+	// accept only JSON request bodies up to MAX_BODY_BYTES, errors are sent in JSON
+	router.Use(handlers.HandlerRequestBody(data.maxBodyBytes))
 
 	// Handle Error 404
 	router.NotFoundHandler = http.HandlerFunc(handlers.HandlerNotFound)
@@ -29,28 +50,107 @@ func ServerRouter() {
 	// Handle Method Not Allowed
 	router.MethodNotAllowedHandler = http.HandlerFunc(handlers.HandlerMethodNotAllowed)
 
+	// per route concurrency limits from ROUTE_CONCURRENCY
+	if len(data.routeConcurrency) > 0 {
+		router.Use(handlers.HandlerConcurrency(data.routeConcurrency, data.routeQueueSize, data.routeQueueTimeout))
+	}
+
+	// management routes share the public router unless an admin port is set
+	admin = router
+
+	if data.adminPort != "" {
+
+		admin = mux.NewRouter().StrictSlash(true)
+		admin.Use(handlers.HandlerTrace)
+		admin.NotFoundHandler = http.HandlerFunc(handlers.HandlerNotFound)
+		admin.MethodNotAllowedHandler = http.HandlerFunc(handlers.HandlerMethodNotAllowed)
+
+	}
+
+	// connection metrics
+	openapi.Returns(admin.HandleFunc("/metrics/connections", connections.Metrics).Methods("GET"), interfaces.IConnectionMetrics{})
+
+	// calls to deprecated routes
+	openapi.Returns(admin.HandleFunc("/metrics/deprecations", handlers.HandlerDeprecationMetrics).Methods("GET"), interfaces.IDeprecationMetrics{})
+
+	// Deprecation / Sunset headers on the routes listed in DEPRECATED_ROUTES
+	if len(data.deprecatedRoutes) > 0 {
+		router.Use(handlers.HandlerDeprecation(data.deprecatedRoutes, data.deprecatedSince, data.deprecatedGone))
+	}
+
+	// build metadata
+	openapi.Returns(router.HandleFunc("/version", handlers.HandlerVersion([]string{strings.TrimPrefix(data.apiVersion, "/")})).Methods("GET"), interfaces.IVersionResponse{})
+
+	// optional subsystems enabled on this deployment
+	openapi.Returns(router.HandleFunc("/.well-known/api-capabilities", capabilities).Methods("GET"), interfaces.ICapabilities{})
+
+	// OpenAPI document of the public routes and its documentation page, neither
+	// answers in the response envelope so both are left out of the document
+	openapi.Hidden(router.HandleFunc("/openapi.json", openapi.Handler(router, "go-rest-api", version.Version)).Methods("GET"))
+	openapi.Hidden(router.HandleFunc("/docs", openapi.DocsHandler(data.docsIntegrity)).Methods("GET"))
+
+	// readiness probe, aggregates the checks in health.Registry
+	openapi.Returns(router.HandleFunc("/readyz", handlers.HandlerReadyz).Methods("GET"), interfaces.IReadyResponse{}, http.StatusOK, http.StatusServiceUnavailable)
+
+	// public status page, no authorization required, cached and rate limited by
+	// IP on its own budget, even when /v1 is not rate limited
+	publicStatus := http.Handler(resources.ResourcePublicStatus(version.Version))
+
+	if data.publicStatusRateLimit > 0 {
+		publicStatus = handlers.HandlerRateLimit(data.publicStatusRateLimit, 0, handlers.RateLimitByIP, data.rateLimitTrustProxy)(publicStatus)
+	}
+
+	openapi.Returns(router.Handle("/public/status", publicStatus).Methods("GET"), interfaces.IPublicStatus{})
+
 	// subrouter so it can be used a version previously to any resource
 	path := router.PathPrefix(data.apiVersion).Subrouter()
 
 	// request handler resource
 	path.Use(handlers.HandlerRequestHandler)
 
+	// HMAC signed requests from partner clients
+	if len(data.signingClients) > 0 {
+		path.Use(handlers.HandlerRequestSignature(data.signingClients, data.signingTolerance))
+	}
+
+	// per caller rate limit, after the signature so signed clients get their own bucket
+	if data.rateLimit > 0 {
+		path.Use(handlers.HandlerRateLimit(data.rateLimit, data.rateLimitBurst, data.rateLimitKey, data.rateLimitTrustProxy))
+	}
+
 	// log.Println(auth.AuthorizationBearerToken(http.))
 
 	// index resource
-	path.HandleFunc("/", resources.ResourceIndex).Methods("GET")
+	openapi.Secured(path.HandleFunc("/", resources.ResourceIndex).Methods("GET"))
 
 	// users resource
-	path.HandleFunc("/users/{id}", resources.ResourceUsers).Methods("POST")
+	openapi.Accepts(openapi.Secured(path.HandleFunc("/users/{id}", resources.ResourceUsers).Methods("POST")), resources.PostData{})
 
-	// print text to let knoe the server is running
-	log.Println("Listenting on Port: " + data.port)
+	// message of the day resource, anyone can read it, changing it needs a token
+	path.HandleFunc("/motd", resources.ResourceMotd).Methods("GET")
+	openapi.Accepts(openapi.Secured(path.HandleFunc("/motd", resources.ResourceMotd).Methods("PUT")), resources.MotdData{})
 
-	// start server or log error
-	err := http.ListenAndServe(":"+data.port, router)
+	// collect the registered routes for "did you mean" suggestions on 404
+	var templates []string
 
-	if err != nil {
-		log.Fatal("Server Start Error: " + err.Error())
-	}
+	seen := map[string]bool{}
+
+	router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+
+		template, err := route.GetPathTemplate()
+
+		// a path registered once per method is suggested once
+		if err == nil && route.GetHandler() != nil && !seen[template] {
+			seen[template] = true
+			templates = append(templates, template)
+		}
+
+		return nil
+
+	})
+
+	handlers.SetRoutes(templates)
+
+	return router, admin
 
 }
@@ -0,0 +1,160 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	interfaces "github.com/m4r4v/go-rest-api/interfaces"
+)
+
+// selfTestStep is one request of the smoke flow, check inspects the decoded
+// body when the status code alone is not enough
+type selfTestStep struct {
+	name, method, path, body string
+	authorized, admin, html  bool
+	expected                 int
+	check                    func(body map[string]interface{}) string
+}
+
+var selfTestSteps = []selfTestStep{
+	{name: "readiness", method: "GET", path: "/readyz", expected: http.StatusOK},
+	{name: "version", method: "GET", path: "/version", expected: http.StatusOK},
+	{name: "capabilities", method: "GET", path: "/.well-known/api-capabilities", expected: http.StatusOK},
+	{name: "openapi", method: "GET", path: "/openapi.json", expected: http.StatusOK},
+	{name: "docs", method: "GET", path: "/docs", html: true, expected: http.StatusOK},
+	{name: "public status", method: "GET", path: "/public/status", expected: http.StatusOK},
+	{name: "connection metrics", method: "GET", path: "/metrics/connections", admin: true, expected: http.StatusOK},
+	{name: "deprecation metrics", method: "GET", path: "/metrics/deprecations", admin: true, expected: http.StatusOK},
+	{name: "index forbidden without token", method: "GET", path: "/v1/", expected: http.StatusForbidden},
+	{name: "index", method: "GET", path: "/v1/", authorized: true, expected: http.StatusOK},
+	{name: "set motd", method: "PUT", path: "/v1/motd", body: `{"message":"self-test"}`, authorized: true, expected: http.StatusOK},
+	{name: "get motd", method: "GET", path: "/v1/motd", expected: http.StatusOK, check: expectMessage("self-test")},
+	{name: "reset motd", method: "PUT", path: "/v1/motd", body: `{"message":""}`, authorized: true, expected: http.StatusOK},
+	{name: "users", method: "POST", path: "/v1/users/1", body: `{"username":"nano@gmail.com","password":"self-test"}`, authorized: true, expected: http.StatusOK},
+	{name: "not found", method: "GET", path: "/v1/self-test", expected: http.StatusNotFound},
+	{name: "method not allowed", method: "DELETE", path: "/v1/motd", expected: http.StatusMethodNotAllowed},
+}
+
+func expectMessage(message string) func(body map[string]interface{}) string {
+	return func(body map[string]interface{}) string {
+
+		// the v2 envelope carries the payload in data
+		if payload, ok := body["data"].(map[string]interface{}); ok {
+			body = payload
+		}
+
+		if body["message"] != message {
+			return "unexpected message"
+		}
+
+		return ""
+
+	}
+}
+
+// SelfTest boots the routers in memory, runs the smoke flow and writes a JSON
+// report to out, it returns false if any step failed
+func SelfTest(out io.Writer) bool {
+
+	router, admin := NewRouter()
+
+	public := httptest.NewServer(router)
+	defer public.Close()
+
+	management := public
+
+	if admin != router {
+		management = httptest.NewServer(admin)
+		defer management.Close()
+	}
+
+	report := &interfaces.ISelfTestReport{Passed: true}
+
+	for _, step := range selfTestSteps {
+
+		server := public
+
+		if step.admin {
+			server = management
+		}
+
+		result := runSelfTestStep(server, step)
+
+		report.Passed = report.Passed && result.Passed
+		report.Steps = append(report.Steps, result)
+
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(report)
+
+	return report.Passed
+
+}
+
+func runSelfTestStep(server *httptest.Server, step selfTestStep) *interfaces.ISelfTestStep {
+
+	result := &interfaces.ISelfTestStep{
+		Name:     step.name,
+		Method:   step.method,
+		Path:     step.path,
+		Expected: step.expected,
+	}
+
+	request, err := http.NewRequest(step.method, server.URL+step.path, strings.NewReader(step.body))
+
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	if step.authorized {
+		request.Header.Set("Authorization", "Bearer ok")
+	}
+
+	response, err := server.Client().Do(request)
+
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	defer response.Body.Close()
+
+	result.Actual = response.StatusCode
+
+	// the documentation page is the only route not answering JSON
+	if step.html {
+
+		if !strings.HasPrefix(response.Header.Get("Content-Type"), "text/html") {
+			result.Error = "unexpected content type " + response.Header.Get("Content-Type")
+		}
+
+		result.Passed = result.Actual == result.Expected && result.Error == ""
+
+		return result
+
+	}
+
+	var body map[string]interface{}
+
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		result.Error = "invalid JSON body: " + err.Error()
+		return result
+	}
+
+	if step.check != nil {
+		result.Error = step.check(body)
+	}
+
+	result.Passed = result.Actual == result.Expected && result.Error == ""
+
+	return result
+
+}
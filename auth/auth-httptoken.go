@@ -1,7 +1,6 @@
 package auth
 
 import (
-	"log"
 	"strings"
 )
 
@@ -9,8 +8,9 @@ func AuthorizationBearerToken(t string) bool {
 
 	token := strings.Split(t, "Bearer")
 
+	// missing or malformed header
 	if len(token) != 2 {
-		log.Fatal("Error in Bearer Token")
+		return false
 	}
 
 	// Sha256 | 64 bits
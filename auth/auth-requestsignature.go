@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type signedClientKey struct{}
+
+// RequestSignaturePayload is the string partners sign, one field per line,
+// requestURI is the path with its query string so parameters can not be
+// changed after signing
+func RequestSignaturePayload(method, requestURI, timestamp, nonce string, body []byte) []byte {
+	return []byte(method + "\n" + requestURI + "\n" + timestamp + "\n" + nonce + "\n" + string(body))
+}
+
+// VerifyRequestSignature checks the hex HMAC-SHA256 of a signed partner
+// request and that its timestamp is within tolerance
+func VerifyRequestSignature(method, requestURI, timestamp, nonce string, body []byte, signature, secret string, tolerance time.Duration) bool {
+
+	if !withinTolerance(timestamp, tolerance) {
+		return false
+	}
+
+	return equalHMAC(computeHMAC(secret, RequestSignaturePayload(method, requestURI, timestamp, nonce, body)), signature)
+
+}
+
+// WithSignedClient marks the request context as authenticated by a signature
+func WithSignedClient(ctx context.Context, clientID string) context.Context {
+	return context.WithValue(ctx, signedClientKey{}, clientID)
+}
+
+// SignedClient returns the client id of a signed request, empty otherwise
+func SignedClient(ctx context.Context) string {
+
+	clientID, _ := ctx.Value(signedClientKey{}).(string)
+
+	return clientID
+
+}
+
+// AuthorizationRequest accepts either a verified request signature or a
+// bearer token
+func AuthorizationRequest(r *http.Request) bool {
+
+	if SignedClient(r.Context()) != "" {
+		return true
+	}
+
+	return AuthorizationBearerToken(r.Header.Get("Authorization"))
+
+}
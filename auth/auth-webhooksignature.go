@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m4r4v/go-rest-api/clock"
+)
+
+// computeHMAC returns the hex encoded HMAC-SHA256 of payload
+func computeHMAC(secret string, payload []byte) string {
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	return hex.EncodeToString(mac.Sum(nil))
+
+}
+
+// equalHMAC compares two hex signatures in constant time
+func equalHMAC(expected, actual string) bool {
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(actual)))
+}
+
+// withinTolerance checks a unix timestamp against the current time, a zero
+// tolerance disables the check
+func withinTolerance(timestamp string, tolerance time.Duration) bool {
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+
+	if err != nil {
+		return false
+	}
+
+	if tolerance == 0 {
+		return true
+	}
+
+	age := clock.Since(time.Unix(seconds, 0))
+
+	return age <= tolerance && age >= -tolerance
+
+}
+
+// VerifyGitHubSignature checks the X-Hub-Signature-256 header ("sha256=<hex>")
+func VerifyGitHubSignature(body []byte, signature, secret string) bool {
+
+	if !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+
+	return equalHMAC(computeHMAC(secret, body), strings.TrimPrefix(signature, "sha256="))
+
+}
+
+// VerifyStripeSignature checks the Stripe-Signature header
+// ("t=<unix>,v1=<hex>[,v1=<hex>]"), any v1 signature may match
+func VerifyStripeSignature(body []byte, signature, secret string, tolerance time.Duration) bool {
+
+	var timestamp string
+	var signatures []string
+
+	for _, part := range strings.Split(signature, ",") {
+
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+
+		if !found {
+			continue
+		}
+
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+
+	}
+
+	if timestamp == "" || !withinTolerance(timestamp, tolerance) {
+		return false
+	}
+
+	expected := computeHMAC(secret, []byte(timestamp+"."+string(body)))
+
+	for _, actual := range signatures {
+		if equalHMAC(expected, actual) {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+// VerifySlackSignature checks the X-Slack-Signature header ("v0=<hex>")
+// against the X-Slack-Request-Timestamp header
+func VerifySlackSignature(body []byte, timestamp, signature, secret string, tolerance time.Duration) bool {
+
+	if !strings.HasPrefix(signature, "v0=") || !withinTolerance(timestamp, tolerance) {
+		return false
+	}
+
+	expected := computeHMAC(secret, []byte("v0:"+timestamp+":"+string(body)))
+
+	return equalHMAC(expected, strings.TrimPrefix(signature, "v0="))
+
+}
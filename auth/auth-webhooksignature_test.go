@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m4r4v/go-rest-api/clock"
+)
+
+// example from GitHub's "Validating webhook deliveries" documentation
+const (
+	githubSecret    = "It's a Secret to Everybody"
+	githubBody      = "Hello, World!"
+	githubSignature = "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+)
+
+// example from Slack's "Verifying requests from Slack" documentation
+const (
+	slackSecret    = "8f742231b10e8888abcd99yyyzzz85a5"
+	slackTimestamp = "1531420618"
+	slackBody      = "token=xyzz0WbapA4vBCDEFasx0q6G&team_id=T1DC2JH3J&team_domain=testteamnow&channel_id=G8PSS9T3V&channel_name=foobar&user_id=U2CERLKJA&user_name=roadrunner&command=%2Fwebhook-collect&text=&response_url=https%3A%2F%2Fhooks.slack.com%2Fcommands%2FT1DC2JH3J%2F397700885554%2F96rGlfmibIGlgcZRskXaIFfN&trigger_id=398738663015.47445629121.803a0bc887a14d10d2c447fce8b6703c"
+	slackSignature = "v0=a2114d57b48eac39b9ad189dd8316235a7b4a8d21a10bd27519666489c69b503"
+)
+
+// Stripe publishes no vector with its secret, this one follows the documented
+// scheme, HMAC-SHA256 of "<t>.<payload>" with the endpoint secret
+const (
+	stripeSecret    = "whsec_test"
+	stripeTimestamp = "1492774577"
+	stripeBody      = `{"id":"evt_test","object":"event"}`
+	stripeSignature = "ca9cf86d5bdfe9b9d2500e3a43bc28b83e70db7dc76d5b5aaba8acc029e5687b"
+)
+
+// freezeAt sets the clock to the unix timestamp ts plus offset for the test
+func freezeAt(t *testing.T, ts int64, offset time.Duration) {
+	t.Cleanup(clock.Set(clock.NewFrozenClock(time.Unix(ts, 0).Add(offset))))
+}
+
+func TestVerifyGitHubSignature(t *testing.T) {
+
+	if !VerifyGitHubSignature([]byte(githubBody), githubSignature, githubSecret) {
+		t.Error("documented signature rejected")
+	}
+
+	if VerifyGitHubSignature([]byte(githubBody+"!"), githubSignature, githubSecret) {
+		t.Error("signature accepted for a modified body")
+	}
+
+	if VerifyGitHubSignature([]byte(githubBody), githubSignature, "wrong") {
+		t.Error("signature accepted with the wrong secret")
+	}
+
+	if VerifyGitHubSignature([]byte(githubBody), githubSignature[len("sha256="):], githubSecret) {
+		t.Error("signature accepted without the sha256= prefix")
+	}
+
+}
+
+func TestVerifySlackSignature(t *testing.T) {
+
+	freezeAt(t, 1531420618, time.Minute)
+
+	if !VerifySlackSignature([]byte(slackBody), slackTimestamp, slackSignature, slackSecret, 5*time.Minute) {
+		t.Error("documented signature rejected")
+	}
+
+	if VerifySlackSignature([]byte(slackBody+"&x=1"), slackTimestamp, slackSignature, slackSecret, 5*time.Minute) {
+		t.Error("signature accepted for a modified body")
+	}
+
+	if VerifySlackSignature([]byte(slackBody), "1531420619", slackSignature, slackSecret, 5*time.Minute) {
+		t.Error("signature accepted for a different timestamp")
+	}
+
+}
+
+func TestVerifySlackSignatureExpired(t *testing.T) {
+
+	freezeAt(t, 1531420618, 6*time.Minute)
+
+	if VerifySlackSignature([]byte(slackBody), slackTimestamp, slackSignature, slackSecret, 5*time.Minute) {
+		t.Error("signature accepted outside the tolerance")
+	}
+
+	if !VerifySlackSignature([]byte(slackBody), slackTimestamp, slackSignature, slackSecret, 0) {
+		t.Error("signature rejected with the tolerance disabled")
+	}
+
+}
+
+func TestVerifyStripeSignature(t *testing.T) {
+
+	freezeAt(t, 1492774577, 0)
+
+	header := "t=" + stripeTimestamp + ",v1=" + stripeSignature
+
+	if !VerifyStripeSignature([]byte(stripeBody), header, stripeSecret, 5*time.Minute) {
+		t.Error("valid signature rejected")
+	}
+
+	// any of several v1 signatures may match, as during secret rolling
+	rolled := "t=" + stripeTimestamp + ",v1=deadbeef,v1=" + stripeSignature + ",v0=ignored"
+
+	if !VerifyStripeSignature([]byte(stripeBody), rolled, stripeSecret, 5*time.Minute) {
+		t.Error("signature rejected among several v1 entries")
+	}
+
+	if VerifyStripeSignature([]byte(stripeBody), "v1="+stripeSignature, stripeSecret, 5*time.Minute) {
+		t.Error("signature accepted without a timestamp")
+	}
+
+	if VerifyStripeSignature([]byte(stripeBody), header, "whsec_other", 5*time.Minute) {
+		t.Error("signature accepted with the wrong secret")
+	}
+
+}
+
+func TestVerifyStripeSignatureExpired(t *testing.T) {
+
+	header := "t=" + stripeTimestamp + ",v1=" + stripeSignature
+
+	freezeAt(t, 1492774577, 5*time.Minute+time.Second)
+
+	if VerifyStripeSignature([]byte(stripeBody), header, stripeSecret, 5*time.Minute) {
+		t.Error("signature accepted after the tolerance")
+	}
+
+	freezeAt(t, 1492774577, -5*time.Minute-time.Second)
+
+	if VerifyStripeSignature([]byte(stripeBody), header, stripeSecret, 5*time.Minute) {
+		t.Error("signature accepted from the future beyond the tolerance")
+	}
+
+}
@@ -0,0 +1,94 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the source of the current time for caches, timestamps and
+// signature tolerances, swapped for a frozen or offset clock in tests
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+var current = struct {
+	sync.RWMutex
+	clock Clock
+}{clock: realClock{}}
+
+// Set replaces the clock used by the server and returns a func restoring the
+// previous one
+func Set(c Clock) func() {
+
+	current.Lock()
+	defer current.Unlock()
+
+	previous := current.clock
+	current.clock = c
+
+	return func() {
+		current.Lock()
+		current.clock = previous
+		current.Unlock()
+	}
+
+}
+
+// Now returns the current time of the active clock
+func Now() time.Time {
+
+	current.RLock()
+	defer current.RUnlock()
+
+	return current.clock.Now()
+
+}
+
+// Since returns the time elapsed since t according to the active clock
+func Since(t time.Time) time.Duration {
+	return Now().Sub(t)
+}
+
+// FrozenClock always returns the same instant until it is moved
+type FrozenClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func NewFrozenClock(t time.Time) *FrozenClock {
+	return &FrozenClock{t: t}
+}
+
+func (f *FrozenClock) Now() time.Time {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.t
+
+}
+
+// Advance moves the frozen instant forward by d
+func (f *FrozenClock) Advance(d time.Duration) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.t = f.t.Add(d)
+
+}
+
+// OffsetClock runs in real time shifted by Offset
+type OffsetClock struct {
+	Offset time.Duration
+}
+
+func (o OffsetClock) Now() time.Time {
+	return time.Now().Add(o.Offset)
+}
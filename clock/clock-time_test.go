@@ -0,0 +1,41 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetAndRestore(t *testing.T) {
+
+	instant := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	frozen := NewFrozenClock(instant)
+
+	restore := Set(frozen)
+
+	if !Now().Equal(instant) {
+		t.Fatalf("Now() = %v, want %v", Now(), instant)
+	}
+
+	frozen.Advance(time.Hour)
+
+	if Since(instant) != time.Hour {
+		t.Fatalf("Since() = %v after advancing an hour", Since(instant))
+	}
+
+	restore()
+
+	if time.Since(Now()) > time.Minute {
+		t.Fatalf("Now() = %v after restore, want the real time", Now())
+	}
+
+}
+
+func TestOffsetClock(t *testing.T) {
+
+	defer Set(OffsetClock{Offset: 24 * time.Hour})()
+
+	if ahead := Now().Sub(time.Now()); ahead < 23*time.Hour || ahead > 25*time.Hour {
+		t.Fatalf("offset clock is %v ahead, want about 24h", ahead)
+	}
+
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/m4r4v/go-rest-api/interfaces"
+)
+
+// routeSemaphore allows limit requests at once and up to queueSize more
+// waiting for a free slot
+type routeSemaphore struct {
+	slots     chan struct{}
+	waiting   atomic.Int64
+	queueSize int64
+}
+
+// acquire reports whether a slot was obtained before timeout
+func (s *routeSemaphore) acquire(r *http.Request, timeout time.Duration) bool {
+
+	select {
+	case s.slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	// queue full, reject right away
+	if s.waiting.Add(1) > s.queueSize {
+		s.waiting.Add(-1)
+		return false
+	}
+
+	defer s.waiting.Add(-1)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case s.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-r.Context().Done():
+		return false
+	}
+
+}
+
+func (s *routeSemaphore) release() {
+	<-s.slots
+}
+
+// HandlerConcurrency limits the simultaneous requests of the route templates
+// listed in limits, extra requests wait up to timeout in a queue of queueSize
+// and get 429 when the queue is full or the wait times out
+func HandlerConcurrency(limits map[string]int, queueSize int, timeout time.Duration) mux.MiddlewareFunc {
+
+	semaphores := make(map[string]*routeSemaphore, len(limits))
+
+	for template, limit := range limits {
+		semaphores[template] = &routeSemaphore{
+			slots:     make(chan struct{}, limit),
+			queueSize: int64(queueSize),
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			route := mux.CurrentRoute(r)
+
+			if route == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			template, err := route.GetPathTemplate()
+
+			semaphore, limited := semaphores[template]
+
+			if err != nil || !limited {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !semaphore.acquire(r, timeout) {
+
+				w.Header().Set("Retry-After", strconv.Itoa(int(timeout.Seconds())+1))
+
+				WriteJSON(w, r, http.StatusTooManyRequests, &interfaces.IDefaultResponse{
+					Status:  http.StatusTooManyRequests,
+					Message: "Error 429, too many concurrent requests to this resource",
+				})
+
+				return
+
+			}
+
+			defer semaphore.release()
+
+			next.ServeHTTP(w, r)
+
+		})
+	}
+
+}
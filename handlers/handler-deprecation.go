@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/m4r4v/go-rest-api/clock"
+	"github.com/m4r4v/go-rest-api/interfaces"
+)
+
+// calls received by each deprecated route template
+var deprecationUsage = struct {
+	sync.Mutex
+	calls map[string]int64
+}{calls: map[string]int64{}}
+
+// HandlerDeprecation adds Deprecation and Sunset headers to the routes whose
+// template is listed in sunsets, deprecated since the given time, once the
+// sunset date has passed they answer 410 Gone when gone is true
+func HandlerDeprecation(sunsets map[string]time.Time, since time.Time, gone bool) mux.MiddlewareFunc {
+
+	// RFC 9745 structured field date, @ and unix seconds
+	deprecation := "@" + strconv.FormatInt(since.Unix(), 10)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			route := mux.CurrentRoute(r)
+
+			if route == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			template, err := route.GetPathTemplate()
+
+			sunset, deprecated := sunsets[template]
+
+			if err != nil || !deprecated {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			deprecationUsage.Lock()
+			deprecationUsage.calls[template]++
+			deprecationUsage.Unlock()
+
+			w.Header().Set("Deprecation", deprecation)
+			w.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+
+			if gone && !clock.Now().Before(sunset) {
+
+				WriteJSON(w, r, http.StatusGone, &interfaces.IDefaultResponse{
+					Status:  http.StatusGone,
+					Message: "Error 410, this resource was retired on " + sunset.Format("2006-01-02"),
+				})
+
+				return
+
+			}
+
+			next.ServeHTTP(w, r)
+
+		})
+	}
+
+}
+
+// HandlerDeprecationMetrics serves the call count of every deprecated route
+func HandlerDeprecationMetrics(w http.ResponseWriter, r *http.Request) {
+
+	deprecationUsage.Lock()
+
+	calls := make(map[string]int64, len(deprecationUsage.calls))
+
+	for template, count := range deprecationUsage.calls {
+		calls[template] = count
+	}
+
+	deprecationUsage.Unlock()
+
+	WriteJSON(w, r, http.StatusOK, &interfaces.IDeprecationMetrics{
+		Status: http.StatusOK,
+		Calls:  calls,
+	})
+
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/m4r4v/go-rest-api/clock"
+)
+
+func TestHandlerDeprecation(t *testing.T) {
+
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	frozen := clock.NewFrozenClock(sunset.Add(-time.Hour))
+	t.Cleanup(clock.Set(frozen))
+
+	router := mux.NewRouter()
+	router.Use(HandlerDeprecation(map[string]time.Time{"/v1/old": sunset}, since, true))
+	router.HandleFunc("/v1/old", func(w http.ResponseWriter, r *http.Request) {})
+	router.HandleFunc("/v1/new", func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		name        string
+		path        string
+		advance     time.Duration
+		status      int
+		deprecation string
+		sunset      string
+	}{
+		{"current route", "/v1/new", 0, http.StatusOK, "", ""},
+		{"before the sunset", "/v1/old", 0, http.StatusOK, "@1767225600", "Mon, 01 Jun 2026 00:00:00 GMT"},
+		{"after the sunset", "/v1/old", time.Hour, http.StatusGone, "@1767225600", "Mon, 01 Jun 2026 00:00:00 GMT"},
+	}
+
+	for _, test := range tests {
+
+		frozen.Advance(test.advance)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+
+		if w.Code != test.status {
+			t.Errorf("%s: status %d, want %d", test.name, w.Code, test.status)
+		}
+
+		if got := w.Header().Get("Deprecation"); got != test.deprecation {
+			t.Errorf("%s: Deprecation %q, want %q", test.name, got, test.deprecation)
+		}
+
+		if got := w.Header().Get("Sunset"); got != test.sunset {
+			t.Errorf("%s: Sunset %q, want %q", test.name, got, test.sunset)
+		}
+
+	}
+
+}
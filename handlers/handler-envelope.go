@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/m4r4v/go-rest-api/interfaces"
+	"github.com/m4r4v/go-rest-api/version"
+)
+
+// response envelopes
+const (
+	EnvelopeLegacy = "legacy"
+	EnvelopeV2     = "v2"
+)
+
+var envelope atomic.Value
+
+// SetEnvelope selects the envelope written by WriteJSON, anything other than
+// EnvelopeV2 keeps the legacy one
+func SetEnvelope(name string) {
+
+	if name != EnvelopeV2 {
+		name = EnvelopeLegacy
+	}
+
+	envelope.Store(name)
+
+}
+
+// Envelope returns the envelope written by WriteJSON
+func Envelope() string {
+
+	name, ok := envelope.Load().(string)
+
+	if !ok {
+		return EnvelopeLegacy
+	}
+
+	return name
+
+}
+
+// wrapEnvelope turns a legacy response into the v2 envelope
+func wrapEnvelope(httpStatus int, jsonResponse []byte) ([]byte, error) {
+
+	var payload map[string]interface{}
+
+	if err := json.Unmarshal(jsonResponse, &payload); err != nil {
+		return nil, err
+	}
+
+	// the status lives at the top of the envelope
+	delete(payload, "status-code")
+
+	wrapped := &interfaces.IEnvelope{
+		Success: httpStatus < http.StatusBadRequest,
+		Status:  httpStatus,
+		App: interfaces.IAppInfo{
+			Name:    "go-rest-api",
+			Version: version.Version,
+		},
+	}
+
+	if wrapped.Success {
+		wrapped.Data = payload
+	} else {
+		wrapped.Error = &interfaces.IEnvelopeError{
+			Message: payload["message"],
+			Details: errorDetails(payload),
+		}
+	}
+
+	return json.Marshal(wrapped)
+
+}
+
+// errorDetails keeps every field of an error payload besides its message, so
+// the v2 envelope carries the same information as the legacy one, such as
+// the checks of a failing /readyz
+func errorDetails(payload map[string]interface{}) interface{} {
+
+	details, ok := payload["details"].(map[string]interface{})
+
+	if !ok {
+		details = map[string]interface{}{}
+	}
+
+	for key, value := range payload {
+		if key != "message" && key != "details" {
+			details[key] = value
+		}
+	}
+
+	if len(details) == 0 {
+		return nil
+	}
+
+	return details
+
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/m4r4v/go-rest-api/interfaces"
+)
+
+func useEnvelope(t *testing.T, name string) {
+
+	previous := Envelope()
+	SetEnvelope(name)
+	t.Cleanup(func() { SetEnvelope(previous) })
+
+}
+
+func TestWrapEnvelopeKeepsErrorFields(t *testing.T) {
+
+	useEnvelope(t, EnvelopeV2)
+
+	w := httptest.NewRecorder()
+
+	WriteJSON(w, httptest.NewRequest("GET", "/readyz", nil), http.StatusServiceUnavailable, &interfaces.IReadyResponse{
+		Status:  http.StatusServiceUnavailable,
+		Message: "Error 503, one or more dependencies are not ready",
+		Checks:  map[string]string{"db": "timeout"},
+	})
+
+	var body interfaces.IEnvelope
+
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{"checks": map[string]interface{}{"db": "timeout"}}
+
+	if body.Success || body.Status != http.StatusServiceUnavailable || !reflect.DeepEqual(body.Error.Details, want) {
+		t.Errorf("envelope %+v, error %+v, want details %v", body, body.Error, want)
+	}
+
+}
+
+func TestWrapEnvelopeMergesDetails(t *testing.T) {
+
+	wrapped, err := wrapEnvelope(http.StatusBadRequest, []byte(`{"status-code":400,"message":"bad","details":{"field":"name"},"suggestions":["/v1/"]}`))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var body interfaces.IEnvelope
+
+	if err := json.Unmarshal(wrapped, &body); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{"field": "name", "suggestions": []interface{}{"/v1/"}}
+
+	if body.Error.Message != "bad" || !reflect.DeepEqual(body.Error.Details, want) {
+		t.Errorf("error %+v, want details %v", body.Error, want)
+	}
+
+}
+
+func TestWrapEnvelopeOmitsEmptyDetails(t *testing.T) {
+
+	wrapped, err := wrapEnvelope(http.StatusNotFound, []byte(`{"status-code":404,"message":"missing"}`))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var body struct {
+		Error map[string]interface{} `json:"error"`
+	}
+
+	if err := json.Unmarshal(wrapped, &body); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := body.Error["details"]; ok {
+		t.Errorf("error %v, want no details", body.Error)
+	}
+
+}
+
+func TestInternalServerErrorFallbacks(t *testing.T) {
+
+	var legacy interfaces.IDefaultResponse
+
+	if err := json.Unmarshal(internalServerError, &legacy); err != nil || legacy.Status != http.StatusInternalServerError {
+		t.Errorf("legacy fallback %s: %v", internalServerError, err)
+	}
+
+	var v2 interfaces.IEnvelope
+
+	if err := json.Unmarshal(internalServerErrorV2, &v2); err != nil || v2.Success || v2.Status != http.StatusInternalServerError || v2.Error == nil {
+		t.Errorf("v2 fallback %s: %v", internalServerErrorV2, err)
+	}
+
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// fieldTree holds the requested fields, a key with an empty subtree selects
+// the whole value, a key with children selects only those nested keys
+type fieldTree map[string]fieldTree
+
+// parseFields turns "id,name,data.title" into a fieldTree, nil if empty
+func parseFields(query string) fieldTree {
+
+	tree := fieldTree{}
+
+	for _, field := range strings.Split(query, ",") {
+
+		field = strings.TrimSpace(field)
+
+		if field == "" {
+			continue
+		}
+
+		node := tree
+
+		for _, key := range strings.Split(field, ".") {
+
+			if node[key] == nil {
+				node[key] = fieldTree{}
+			}
+
+			node = node[key]
+
+		}
+
+	}
+
+	if len(tree) == 0 {
+		return nil
+	}
+
+	return tree
+
+}
+
+// apply keeps only the selected keys of objects, arrays are filtered item by item
+func (t fieldTree) apply(v interface{}) interface{} {
+
+	if len(t) == 0 {
+		return v
+	}
+
+	switch value := v.(type) {
+
+	case map[string]interface{}:
+
+		selected := make(map[string]interface{}, len(t))
+
+		for key, subtree := range t {
+			if nested, ok := value[key]; ok {
+				selected[key] = subtree.apply(nested)
+			}
+		}
+
+		return selected
+
+	case []interface{}:
+
+		for i := range value {
+			value[i] = t.apply(value[i])
+		}
+
+		return value
+
+	}
+
+	return v
+
+}
+
+// filterFields applies the ?fields= query parameter to an encoded response
+func filterFields(r *http.Request, jsonResponse []byte) ([]byte, error) {
+
+	tree := parseFields(r.URL.Query().Get("fields"))
+
+	if tree == nil {
+		return jsonResponse, nil
+	}
+
+	var decoded interface{}
+
+	if err := json.Unmarshal(jsonResponse, &decoded); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(tree.apply(decoded))
+
+}
@@ -1,8 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
-	"log"
 	"net/http"
 
 	"github.com/m4r4v/go-rest-api/interfaces"
@@ -12,19 +10,11 @@ func HandlerMethodNotAllowed(w http.ResponseWriter, r *http.Request) {
 
 	httpStatus := http.StatusMethodNotAllowed
 
-	response = &interfaces.IDefaultResponse{
+	response := &interfaces.IDefaultResponse{
 		Status:  httpStatus,
 		Message: "Error 405, your request method is not allowed",
 	}
 
-	jsonResponse, err := json.Marshal(response)
-
-	if err != nil {
-		log.Fatal("Error 405: " + err.Error())
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(httpStatus)
-	w.Write(jsonResponse)
+	WriteJSON(w, r, httpStatus, response)
 
 }
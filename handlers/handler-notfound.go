@@ -1,8 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
-	"log"
 	"net/http"
 
 	"github.com/m4r4v/go-rest-api/interfaces"
@@ -12,19 +10,18 @@ func HandlerNotFound(w http.ResponseWriter, r *http.Request) {
 
 	httpStatus := http.StatusNotFound
 
-	response = &interfaces.IDefaultResponse{
+	response := &interfaces.IDefaultResponse{
 		Status:  httpStatus,
 		Message: "Error 404, your request was not found",
 	}
 
-	jsonResponse, err := json.Marshal(response)
-
-	if err != nil {
-		log.Fatal("Error 404: " + err.Error())
+	// point the client to the closest registered routes
+	if suggestions := suggestRoutes(r.URL.Path); len(suggestions) > 0 {
+		response.Details = map[string]interface{}{
+			"suggestions": suggestions,
+		}
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(httpStatus)
-	w.Write(jsonResponse)
+	WriteJSON(w, r, httpStatus, response)
 
 }
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/m4r4v/go-rest-api/auth"
+	"github.com/m4r4v/go-rest-api/clock"
+	"github.com/m4r4v/go-rest-api/interfaces"
+)
+
+// rate limit keys
+const (
+	RateLimitByIP     = "ip"
+	RateLimitByClient = "client"
+)
+
+type rateBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter is a token bucket per key refilled at perMinute tokens a minute
+// up to burst
+type rateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*rateBucket
+	perMinute float64
+	burst     float64
+	swept     time.Time
+}
+
+// take removes a token from the bucket of key, it returns whether the request
+// is allowed, the tokens left, how long until the next token and how long
+// until the bucket is full again
+func (l *rateLimiter) take(key string) (allowed bool, remaining int, retry, reset time.Duration) {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := clock.Now()
+	rate := l.perMinute / 60
+
+	// forget buckets that refilled completely, once a minute
+	if now.Sub(l.swept) > time.Minute {
+
+		for k, bucket := range l.buckets {
+			if bucket.tokens+now.Sub(bucket.last).Seconds()*rate >= l.burst {
+				delete(l.buckets, k)
+			}
+		}
+
+		l.swept = now
+
+	}
+
+	bucket, found := l.buckets[key]
+
+	if !found {
+		bucket = &rateBucket{tokens: l.burst, last: now}
+		l.buckets[key] = bucket
+	}
+
+	bucket.tokens = math.Min(l.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*rate)
+	bucket.last = now
+
+	allowed = bucket.tokens >= 1
+
+	if allowed {
+		bucket.tokens--
+	} else {
+		retry = time.Duration((1 - bucket.tokens) / rate * float64(time.Second))
+	}
+
+	reset = time.Duration((l.burst - bucket.tokens) / rate * float64(time.Second))
+
+	return allowed, int(bucket.tokens), retry, reset
+
+}
+
+// HandlerRateLimit allows perMinute requests a minute with bursts of up to
+// burst for every IP, or for every authenticated client when keyBy is
+// RateLimitByClient, over the limit it answers 429. X-RateLimit-Reset is
+// always the seconds until the bucket is full again, a 429 also carries
+// Retry-After, the seconds until the next request is allowed
+func HandlerRateLimit(perMinute, burst int, keyBy string, trustProxy bool) mux.MiddlewareFunc {
+
+	if burst < 1 {
+		burst = perMinute
+	}
+
+	limiter := &rateLimiter{
+		buckets:   map[string]*rateBucket{},
+		perMinute: float64(perMinute),
+		burst:     float64(burst),
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			allowed, remaining, retry, reset := limiter.take(rateLimitKey(r, keyBy, trustProxy))
+
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(perMinute))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+			w.Header().Set("X-RateLimit-Reset", ceilSeconds(reset))
+
+			if !allowed {
+
+				seconds := ceilSeconds(retry)
+
+				w.Header().Set("Retry-After", seconds)
+
+				WriteJSON(w, r, http.StatusTooManyRequests, &interfaces.IDefaultResponse{
+					Status:  http.StatusTooManyRequests,
+					Message: "Error 429, rate limit exceeded, retry in " + seconds + " seconds",
+				})
+
+				return
+
+			}
+
+			next.ServeHTTP(w, r)
+
+		})
+	}
+
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+// rateLimitKey identifies the caller. Signed clients are verified, so their
+// id is a key on its own. Bearer tokens are only checked for shape, so the
+// normalized token is combined with the IP and a client can not get a fresh
+// bucket by padding or inventing tokens from the same address
+func rateLimitKey(r *http.Request, keyBy string, trustProxy bool) string {
+
+	ip := clientIP(r, trustProxy)
+
+	if keyBy == RateLimitByClient {
+
+		if client := auth.SignedClient(r.Context()); client != "" {
+			return "client:" + client
+		}
+
+		if header := r.Header.Get("Authorization"); auth.AuthorizationBearerToken(header) {
+
+			token := strings.TrimSpace(strings.SplitN(header, "Bearer", 2)[1])
+			sum := sha256.Sum256([]byte(token))
+
+			return "token:" + hex.EncodeToString(sum[:]) + ":" + ip
+
+		}
+
+	}
+
+	return "ip:" + ip
+
+}
+
+// clientIP returns the remote address, or behind a trusted proxy the last
+// X-Forwarded-For entry, the one the proxy appended, since earlier entries
+// are sent by the client and can be anything
+func clientIP(r *http.Request, trustProxy bool) string {
+
+	if trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+
+			entries := strings.Split(forwarded, ",")
+
+			if last := strings.TrimSpace(entries[len(entries)-1]); last != "" {
+				return last
+			}
+
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m4r4v/go-rest-api/auth"
+	"github.com/m4r4v/go-rest-api/clock"
+)
+
+func TestClientIP(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		trustProxy bool
+		want       string
+	}{
+		{"remote address", "10.0.0.1:1234", "", false, "10.0.0.1"},
+		{"remote address without port", "10.0.0.1", "", false, "10.0.0.1"},
+		{"untrusted proxy header", "10.0.0.1:1234", "1.2.3.4", false, "10.0.0.1"},
+		{"trusted proxy", "10.0.0.1:1234", "1.2.3.4", true, "1.2.3.4"},
+		{"spoofed entries before the proxy's", "10.0.0.1:1234", "6.6.6.6, 1.2.3.4", true, "1.2.3.4"},
+		{"empty last entry", "10.0.0.1:1234", "1.2.3.4, ", true, "10.0.0.1"},
+	}
+
+	for _, test := range tests {
+
+		r := httptest.NewRequest("GET", "/v1/", nil)
+		r.RemoteAddr = test.remoteAddr
+
+		if test.forwarded != "" {
+			r.Header.Set("X-Forwarded-For", test.forwarded)
+		}
+
+		if got := clientIP(r, test.trustProxy); got != test.want {
+			t.Errorf("%s: clientIP = %q, want %q", test.name, got, test.want)
+		}
+
+	}
+
+}
+
+func TestRateLimitKey(t *testing.T) {
+
+	sum := sha256.Sum256([]byte("ab"))
+	token := "token:" + hex.EncodeToString(sum[:]) + ":10.0.0.1"
+
+	tests := []struct {
+		name          string
+		keyBy         string
+		authorization string
+		client        string
+		want          string
+	}{
+		{"by ip", RateLimitByIP, "Bearer ab", "acme", "ip:10.0.0.1"},
+		{"signed client", RateLimitByClient, "Bearer ab", "acme", "client:acme"},
+		{"bearer token", RateLimitByClient, "Bearer ab", "", token},
+		{"padded bearer token", RateLimitByClient, "Bearer   ab  ", "", token},
+		{"malformed token", RateLimitByClient, "Bearer abc", "", "ip:10.0.0.1"},
+		{"anonymous", RateLimitByClient, "", "", "ip:10.0.0.1"},
+	}
+
+	for _, test := range tests {
+
+		r := httptest.NewRequest("GET", "/v1/", nil)
+		r.RemoteAddr = "10.0.0.1:1234"
+
+		if test.authorization != "" {
+			r.Header.Set("Authorization", test.authorization)
+		}
+
+		if test.client != "" {
+			r = r.WithContext(auth.WithSignedClient(r.Context(), test.client))
+		}
+
+		if got := rateLimitKey(r, test.keyBy, false); got != test.want {
+			t.Errorf("%s: rateLimitKey = %q, want %q", test.name, got, test.want)
+		}
+
+	}
+
+}
+
+func TestHandlerRateLimit(t *testing.T) {
+
+	frozen := clock.NewFrozenClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	t.Cleanup(clock.Set(frozen))
+
+	// 6 a minute is a token every 10 seconds, up to 2
+	handler := HandlerRateLimit(6, 2, RateLimitByIP, false)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	request := func() *httptest.ResponseRecorder {
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/v1/", nil)
+		r.RemoteAddr = "10.0.0.1:1234"
+
+		handler.ServeHTTP(w, r)
+
+		return w
+
+	}
+
+	tests := []struct {
+		name       string
+		advance    time.Duration
+		status     int
+		remaining  string
+		reset      string
+		retryAfter string
+	}{
+		{"first of the burst", 0, http.StatusOK, "1", "10", ""},
+		{"last of the burst", 0, http.StatusOK, "0", "20", ""},
+		{"over the limit", 0, http.StatusTooManyRequests, "0", "20", "10"},
+		{"partly refilled", 4 * time.Second, http.StatusTooManyRequests, "0", "16", "6"},
+		{"next token", 6 * time.Second, http.StatusOK, "0", "20", ""},
+		{"refilled", time.Minute, http.StatusOK, "1", "10", ""},
+	}
+
+	for _, test := range tests {
+
+		frozen.Advance(test.advance)
+
+		w := request()
+
+		if w.Code != test.status {
+			t.Errorf("%s: status %d, want %d", test.name, w.Code, test.status)
+		}
+
+		headers := map[string]string{
+			"X-RateLimit-Limit":     "6",
+			"X-RateLimit-Remaining": test.remaining,
+			"X-RateLimit-Reset":     test.reset,
+			"Retry-After":           test.retryAfter,
+		}
+
+		for header, want := range headers {
+			if got := w.Header().Get(header); got != want {
+				t.Errorf("%s: %s = %q, want %q", test.name, header, got, want)
+			}
+		}
+
+	}
+
+}
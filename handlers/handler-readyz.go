@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/m4r4v/go-rest-api/health"
+	"github.com/m4r4v/go-rest-api/interfaces"
+)
+
+func HandlerReadyz(w http.ResponseWriter, r *http.Request) {
+
+	httpStatus := http.StatusOK
+	message := "Ready"
+
+	checks := map[string]string{}
+
+	for name, err := range health.Registry.Check() {
+
+		if err != nil {
+			httpStatus = http.StatusServiceUnavailable
+			message = "Error 503, one or more dependencies are not ready"
+			checks[name] = err.Error()
+			continue
+		}
+
+		checks[name] = "ok"
+
+	}
+
+	WriteJSON(w, r, httpStatus, &interfaces.IReadyResponse{
+		Status:  httpStatus,
+		Message: message,
+		Checks:  checks,
+	})
+
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/m4r4v/go-rest-api/interfaces"
+)
+
+// HandlerRequestBody rejects POST, PUT and PATCH requests that are not
+// application/json with 415 and bodies over maxBytes with 413
+func HandlerRequestBody(maxBytes int64) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			switch r.Method {
+			case http.MethodPost, http.MethodPut, http.MethodPatch:
+			default:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+			if err != nil || mediaType != "application/json" {
+
+				WriteJSON(w, r, http.StatusUnsupportedMediaType, &interfaces.IDefaultResponse{
+					Status:  http.StatusUnsupportedMediaType,
+					Message: "Error 415, the request body must be application/json",
+				})
+
+				return
+
+			}
+
+			// a declared length over the limit is rejected before reading anything
+			if r.ContentLength > maxBytes {
+				WriteBodyError(w, r, &http.MaxBytesError{Limit: maxBytes})
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+
+			next.ServeHTTP(w, r)
+
+		})
+	}
+}
+
+// WriteBodyError answers a failed request body read, 413 when the body went
+// over the limit and 400 otherwise
+func WriteBodyError(w http.ResponseWriter, r *http.Request, err error) {
+
+	var tooLarge *http.MaxBytesError
+
+	if errors.As(err, &tooLarge) {
+
+		WriteJSON(w, r, http.StatusRequestEntityTooLarge, &interfaces.IDefaultResponse{
+			Status:  http.StatusRequestEntityTooLarge,
+			Message: "Error 413, the request body is larger than " + strconv.FormatInt(tooLarge.Limit, 10) + " bytes",
+		})
+
+		return
+
+	}
+
+	WriteJSON(w, r, http.StatusBadRequest, &interfaces.IDefaultResponse{
+		Status:  http.StatusBadRequest,
+		Message: "Error 400, the request body is not valid JSON",
+	})
+
+}
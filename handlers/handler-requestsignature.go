@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/m4r4v/go-rest-api/auth"
+	"github.com/m4r4v/go-rest-api/clock"
+	"github.com/m4r4v/go-rest-api/interfaces"
+)
+
+// nonceCache remembers the nonces of accepted signed requests until their
+// timestamps fall out of the tolerance window
+type nonceCache struct {
+	mu     sync.Mutex
+	nonces map[string]time.Time
+}
+
+// add records a nonce and reports false if it was already used
+func (c *nonceCache) add(nonce string, ttl time.Duration) bool {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := clock.Now()
+
+	for key, expires := range c.nonces {
+		if now.After(expires) {
+			delete(c.nonces, key)
+		}
+	}
+
+	if _, used := c.nonces[nonce]; used {
+		return false
+	}
+
+	c.nonces[nonce] = now.Add(ttl)
+
+	return true
+
+}
+
+// DefaultSignatureTolerance is how far X-Timestamp may be from the server
+// clock when no positive tolerance is configured
+const DefaultSignatureTolerance = 5 * time.Minute
+
+// HandlerRequestSignature verifies requests carrying X-Signature, an HMAC of
+// method, path and query, X-Timestamp, X-Nonce and body using the secret of X-Client-Id.
+// Requests without X-Signature continue to the bearer token check
+func HandlerRequestSignature(clients map[string]string, tolerance time.Duration) mux.MiddlewareFunc {
+
+	// a zero tolerance would accept any timestamp and expire nonces at once,
+	// turning replay protection off
+	if tolerance <= 0 {
+		tolerance = DefaultSignatureTolerance
+	}
+
+	nonces := &nonceCache{nonces: map[string]time.Time{}}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			signature := r.Header.Get("X-Signature")
+
+			if signature == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			clientID := r.Header.Get("X-Client-Id")
+			timestamp := r.Header.Get("X-Timestamp")
+			nonce := r.Header.Get("X-Nonce")
+
+			secret, known := clients[clientID]
+
+			if !known || nonce == "" {
+				unauthorizedSignature(w, r, "Error 401, unknown client or missing nonce")
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+
+			if err != nil {
+				WriteBodyError(w, r, err)
+				return
+			}
+
+			// let the resource read the body again
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			if !auth.VerifyRequestSignature(r.Method, r.URL.RequestURI(), timestamp, nonce, body, signature, secret, tolerance) {
+				unauthorizedSignature(w, r, "Error 401, invalid or expired request signature")
+				return
+			}
+
+			// a nonce stays reserved for as long as its timestamp could be accepted
+			if !nonces.add(clientID+":"+nonce, 2*tolerance) {
+				unauthorizedSignature(w, r, "Error 401, the request nonce was already used")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithSignedClient(r.Context(), clientID)))
+
+		})
+	}
+
+}
+
+func unauthorizedSignature(w http.ResponseWriter, r *http.Request, message string) {
+
+	WriteJSON(w, r, http.StatusUnauthorized, &interfaces.IDefaultResponse{
+		Status:  http.StatusUnauthorized,
+		Message: message,
+	})
+
+}
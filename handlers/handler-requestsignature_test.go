@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/m4r4v/go-rest-api/auth"
+	"github.com/m4r4v/go-rest-api/clock"
+)
+
+const (
+	signingClient = "acme"
+	signingSecret = "s3cret"
+)
+
+var signingNow = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// signedRouter serves /v1/echo behind the signature middleware, echoing the
+// body when the request was authenticated by a signature
+func signedRouter(tolerance time.Duration) *mux.Router {
+
+	router := mux.NewRouter()
+	router.Use(HandlerRequestSignature(map[string]string{signingClient: signingSecret}, tolerance))
+
+	router.HandleFunc("/v1/echo", func(w http.ResponseWriter, r *http.Request) {
+
+		if auth.SignedClient(r.Context()) != signingClient {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+
+	})
+
+	return router
+
+}
+
+// signedRequest signs method, signedURI, nonce and body at the frozen clock
+// time and sends them to uri, which differs from signedURI when tampering
+func signedRequest(method, uri, signedURI, nonce, body string) *http.Request {
+
+	timestamp := strconv.FormatInt(signingNow.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write(auth.RequestSignaturePayload(method, signedURI, timestamp, nonce, []byte(body)))
+
+	r := httptest.NewRequest(method, uri, strings.NewReader(body))
+	r.Header.Set("X-Client-Id", signingClient)
+	r.Header.Set("X-Timestamp", timestamp)
+	r.Header.Set("X-Nonce", nonce)
+	r.Header.Set("X-Signature", hex.EncodeToString(mac.Sum(nil)))
+
+	return r
+
+}
+
+func serve(router http.Handler, r *http.Request) *httptest.ResponseRecorder {
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	return w
+
+}
+
+func TestRequestSignature(t *testing.T) {
+
+	t.Cleanup(clock.Set(clock.NewFrozenClock(signingNow)))
+
+	// the signature covers the method, so a POST signature sent as PUT fails
+	methodChanged := signedRequest("POST", "/v1/echo", "/v1/echo", "n5", "")
+	methodChanged.Method = "PUT"
+
+	tests := []struct {
+		name    string
+		request *http.Request
+		status  int
+	}{
+		{"valid", signedRequest("POST", "/v1/echo", "/v1/echo", "n1", `{"a":1}`), http.StatusOK},
+		{"valid with query", signedRequest("GET", "/v1/echo?fields=a", "/v1/echo?fields=a", "n2", ""), http.StatusOK},
+		{"query added after signing", signedRequest("POST", "/v1/echo?x=1", "/v1/echo", "n3", `{"a":1}`), http.StatusUnauthorized},
+		{"query changed after signing", signedRequest("GET", "/v1/echo?fields=b", "/v1/echo?fields=a", "n4", ""), http.StatusUnauthorized},
+		{"method changed after signing", methodChanged, http.StatusUnauthorized},
+	}
+
+	router := signedRouter(time.Minute)
+
+	for _, test := range tests {
+		if w := serve(router, test.request); w.Code != test.status {
+			t.Errorf("%s: status %d, want %d", test.name, w.Code, test.status)
+		}
+	}
+
+}
+
+func TestRequestSignatureBodyReachesHandler(t *testing.T) {
+
+	t.Cleanup(clock.Set(clock.NewFrozenClock(signingNow)))
+
+	w := serve(signedRouter(time.Minute), signedRequest("POST", "/v1/echo", "/v1/echo", "n1", `{"a":1}`))
+
+	if w.Body.String() != `{"a":1}` {
+		t.Errorf("body %q, want the signed body", w.Body.String())
+	}
+
+}
+
+func TestRequestSignatureRejectsTamperedBody(t *testing.T) {
+
+	t.Cleanup(clock.Set(clock.NewFrozenClock(signingNow)))
+
+	r := signedRequest("POST", "/v1/echo", "/v1/echo", "n1", `{"a":1}`)
+	r.Body = io.NopCloser(strings.NewReader(`{"a":2}`))
+
+	if w := serve(signedRouter(time.Minute), r); w.Code != http.StatusUnauthorized {
+		t.Errorf("status %d, want 401", w.Code)
+	}
+
+}
+
+func TestRequestSignatureRejectsReplay(t *testing.T) {
+
+	// zero must not disable the timestamp check or the nonce cache
+	for _, tolerance := range []time.Duration{time.Minute, 0} {
+
+		frozen := clock.NewFrozenClock(signingNow)
+		restore := clock.Set(frozen)
+
+		router := signedRouter(tolerance)
+
+		if w := serve(router, signedRequest("GET", "/v1/echo", "/v1/echo", "once", "")); w.Code != http.StatusOK {
+			t.Errorf("tolerance %v: first request status %d, want 200", tolerance, w.Code)
+		}
+
+		frozen.Advance(time.Second)
+
+		if w := serve(router, signedRequest("GET", "/v1/echo", "/v1/echo", "once", "")); w.Code != http.StatusUnauthorized {
+			t.Errorf("tolerance %v: replayed request status %d, want 401", tolerance, w.Code)
+		}
+
+		restore()
+
+	}
+
+}
+
+func TestRequestSignatureRejectsExpiredTimestamp(t *testing.T) {
+
+	for _, tolerance := range []time.Duration{time.Minute, 0} {
+
+		// signed at signingNow, received an hour later
+		restore := clock.Set(clock.NewFrozenClock(signingNow.Add(time.Hour)))
+
+		if w := serve(signedRouter(tolerance), signedRequest("GET", "/v1/echo", "/v1/echo", "late", "")); w.Code != http.StatusUnauthorized {
+			t.Errorf("tolerance %v: status %d, want 401", tolerance, w.Code)
+		}
+
+		restore()
+
+	}
+
+}
+
+func TestRequestSignatureUnknownClient(t *testing.T) {
+
+	t.Cleanup(clock.Set(clock.NewFrozenClock(signingNow)))
+
+	r := signedRequest("GET", "/v1/echo", "/v1/echo", "n1", "")
+	r.Header.Set("X-Client-Id", "other")
+
+	if w := serve(signedRouter(time.Minute), r); w.Code != http.StatusUnauthorized {
+		t.Errorf("status %d, want 401", w.Code)
+	}
+
+}
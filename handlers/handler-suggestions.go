@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// maximum number of paths suggested on a 404
+const maxSuggestions = 3
+
+// longer paths are not compared against the routes, no template is close to
+// them and comparing them would cost time on every 404
+const maxSuggestionPath = 256
+
+// registered route templates, filled by the router once all routes are added
+var routes = struct {
+	sync.RWMutex
+	templates []string
+}{}
+
+// SetRoutes stores the route templates used for "did you mean" suggestions
+func SetRoutes(templates []string) {
+
+	routes.Lock()
+	defer routes.Unlock()
+
+	routes.templates = templates
+
+}
+
+// suggestRoutes returns the registered templates closest to path
+func suggestRoutes(path string) []string {
+
+	if len(path) > maxSuggestionPath {
+		return nil
+	}
+
+	routes.RLock()
+	defer routes.RUnlock()
+
+	type match struct {
+		template string
+		distance int
+	}
+
+	var matches []match
+
+	requested := splitPath(path)
+
+	// allow roughly one typo every four characters
+	threshold := len(path)/4 + 1
+
+	for _, template := range routes.templates {
+
+		distance := pathDistance(requested, splitPath(template), threshold)
+
+		if distance > 0 && distance <= threshold {
+			matches = append(matches, match{template, distance})
+		}
+
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].distance < matches[j].distance
+	})
+
+	var suggestions []string
+
+	for i := 0; i < len(matches) && i < maxSuggestions; i++ {
+		suggestions = append(suggestions, matches[i].template)
+	}
+
+	return suggestions
+
+}
+
+func splitPath(path string) []string {
+	return strings.FieldsFunc(path, func(r rune) bool { return r == '/' })
+}
+
+// pathDistance adds up the Levenshtein distance of every path segment, a
+// template variable such as {id} matches any segment, missing or extra
+// segments cost their full length, it stops counting once limit is exceeded
+func pathDistance(requested, template []string, limit int) int {
+
+	distance := 0
+
+	for i := 0; (i < len(requested) || i < len(template)) && distance <= limit; i++ {
+
+		switch {
+		case i >= len(template):
+			distance += len(requested[i])
+		case i >= len(requested):
+			distance += len(template[i])
+		case strings.HasPrefix(template[i], "{"):
+			continue
+		default:
+			distance += levenshtein(requested[i], template[i], limit-distance)
+		}
+
+	}
+
+	return distance
+
+}
+
+// levenshtein returns the edit distance between a and b, or a value above
+// limit as soon as the distance is known to exceed it
+func levenshtein(a, b string, limit int) int {
+
+	if len(a)-len(b) > limit || len(b)-len(a) > limit {
+		return limit + 1
+	}
+
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+
+		current[0] = i
+		closest := i
+
+		for j := 1; j <= len(b); j++ {
+
+			cost := 1
+
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			closest = minInt(closest, current[j])
+
+		}
+
+		// every later row is at least the smallest value of this one
+		if closest > limit {
+			return limit + 1
+		}
+
+		previous, current = current, previous
+
+	}
+
+	return previous[len(b)]
+
+}
+
+func minInt(values ...int) int {
+
+	result := values[0]
+
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+
+	return result
+
+}
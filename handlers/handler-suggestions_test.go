@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSuggestRoutes(t *testing.T) {
+
+	SetRoutes([]string{"/v1/", "/v1/users/{id}", "/v1/motd", "/version", "/readyz"})
+	t.Cleanup(func() { SetRoutes(nil) })
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"/v1/user/1", []string{"/v1/users/{id}"}},
+		{"/v1/mtod", []string{"/v1/motd"}},
+		{"/verison", []string{"/version"}},
+		{"/v1/motd", nil},
+		{"/nothing/like/it", nil},
+		{"/" + strings.Repeat("a", maxSuggestionPath), nil},
+	}
+
+	for _, test := range tests {
+		if got := suggestRoutes(test.path); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("suggestRoutes(%q) = %v, want %v", test.path, got, test.want)
+		}
+	}
+
+}
+
+func TestLevenshtein(t *testing.T) {
+
+	tests := []struct {
+		a, b  string
+		limit int
+		want  int
+	}{
+		{"motd", "motd", 5, 0},
+		{"mtod", "motd", 5, 2},
+		{"users", "user", 5, 1},
+		{"kitten", "sitting", 5, 3},
+		{"kitten", "sitting", 2, 3},
+		{"a", "abcdef", 2, 3},
+		{"abcdef", "uvwxyz", 3, 4},
+	}
+
+	for _, test := range tests {
+		if got := levenshtein(test.a, test.b, test.limit); got != test.want {
+			t.Errorf("levenshtein(%q, %q, %d) = %d, want %d", test.a, test.b, test.limit, got, test.want)
+		}
+	}
+
+}
+
+func TestSuggestRoutesLongPath(t *testing.T) {
+
+	SetRoutes([]string{"/v1/users/{id}", "/v1/motd"})
+	t.Cleanup(func() { SetRoutes(nil) })
+
+	segment := strings.Repeat("a", maxSuggestionPath-10)
+
+	start := time.Now()
+
+	for i := 0; i < 100; i++ {
+		suggestRoutes("/v1/" + segment)
+		suggestRoutes("/" + strings.Repeat(segment, 4096))
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("suggestions for long paths took %v", elapsed)
+	}
+
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+type traceKey struct{}
+
+// W3C traceparent: version-traceid-parentid-flags
+var traceparentPattern = regexp.MustCompile(`^[0-9a-f]{2}-([0-9a-f]{32})-[0-9a-f]{16}-[0-9a-f]{2}$`)
+
+// X-Cloud-Trace-Context: TRACE_ID/SPAN_ID;o=OPTIONS
+var cloudTracePattern = regexp.MustCompile(`^([0-9a-fA-F]{32})(/|;|$)`)
+
+// parseTraceID reads the trace id from the incoming tracing headers and the
+// header it came from, traceparent wins over X-Cloud-Trace-Context, empty if
+// none is valid
+func parseTraceID(r *http.Request) (traceID, header string) {
+
+	if match := traceparentPattern.FindStringSubmatch(strings.TrimSpace(r.Header.Get("traceparent"))); match != nil {
+		return match[1], "traceparent"
+	}
+
+	if match := cloudTracePattern.FindStringSubmatch(strings.TrimSpace(r.Header.Get("X-Cloud-Trace-Context"))); match != nil {
+		return strings.ToLower(match[1]), "X-Cloud-Trace-Context"
+	}
+
+	return "", ""
+
+}
+
+// HandlerTrace keeps the caller's trace id in the request context so logs can
+// be correlated with it, and echoes the tracing header it came from on the
+// response
+func HandlerTrace(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if traceID, header := parseTraceID(r); traceID != "" {
+
+			r = r.WithContext(context.WithValue(r.Context(), traceKey{}, traceID))
+
+			w.Header().Set(header, r.Header.Get(header))
+
+			Log(r, r.Method+" "+r.URL.Path)
+
+		}
+
+		next.ServeHTTP(w, r)
+
+	})
+}
+
+// TraceID returns the trace id of the request, empty if the client sent none
+func TraceID(ctx context.Context) string {
+
+	traceID, _ := ctx.Value(traceKey{}).(string)
+
+	return traceID
+
+}
+
+// Log writes message with the trace id of r, if the client sent one
+func Log(r *http.Request, message string) {
+
+	if traceID := TraceID(r.Context()); traceID != "" {
+		message += " trace=" + traceID
+	}
+
+	log.Println(message)
+
+}
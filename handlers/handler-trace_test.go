@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandlerTrace(t *testing.T) {
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		want   string
+	}{
+		{"traceparent", "traceparent", "00-" + traceID + "-00f067aa0ba902b7-01", traceID},
+		{"cloud trace", "X-Cloud-Trace-Context", "4BF92F3577B34DA6A3CE929D0E0E4736/1;o=1", traceID},
+		{"malformed traceparent", "traceparent", "00-" + traceID + "-01", ""},
+		{"none", "", "", ""},
+	}
+
+	for _, test := range tests {
+
+		var got string
+
+		handler := HandlerTrace(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = TraceID(r.Context())
+		}))
+
+		r := httptest.NewRequest("GET", "/readyz", nil)
+
+		if test.header != "" {
+			r.Header.Set(test.header, test.value)
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if got != test.want {
+			t.Errorf("%s: TraceID = %q, want %q", test.name, got, test.want)
+		}
+
+		echoed := w.Header().Get(test.header)
+
+		if test.want != "" && echoed != test.value {
+			t.Errorf("%s: echoed %s %q, want %q", test.name, test.header, echoed, test.value)
+		}
+
+		if test.want == "" && test.header != "" && echoed != "" {
+			t.Errorf("%s: echoed invalid %s %q", test.name, test.header, echoed)
+		}
+
+	}
+
+}
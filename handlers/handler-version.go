@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+	"runtime"
+
+	"github.com/m4r4v/go-rest-api/interfaces"
+	"github.com/m4r4v/go-rest-api/version"
+)
+
+// HandlerVersion serves the build metadata and the API versions this build
+// supports
+func HandlerVersion(apiVersions []string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		WriteJSON(w, r, http.StatusOK, &interfaces.IVersionResponse{
+			Status:      http.StatusOK,
+			Version:     version.Version,
+			GitCommit:   version.GitCommit,
+			BuildDate:   version.BuildDate,
+			GoVersion:   runtime.Version(),
+			APIVersions: apiVersions,
+		})
+
+	}
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/m4r4v/go-rest-api/interfaces"
+)
+
+// fallback bodies used when even the error envelope can not be encoded
+var (
+	internalServerError   = []byte(`{"status-code":500,"message":"Error 500, the response could not be encoded"}`)
+	internalServerErrorV2 = []byte(`{"success":false,"status":500,"error":{"message":"Error 500, the response could not be encoded"},"app":{"name":"go-rest-api"}}`)
+)
+
+// WriteJSON encodes v before writing anything, so an encoding error never
+// leaves the client with a truncated body, a 500 envelope is sent instead.
+// Successful responses honour the ?fields= query parameter, the result is
+// wrapped in the envelope selected with SetEnvelope
+func WriteJSON(w http.ResponseWriter, r *http.Request, httpStatus int, v interface{}) {
+
+	jsonResponse, err := json.Marshal(v)
+
+	// error envelopes are always sent in full
+	if err == nil && httpStatus < http.StatusBadRequest {
+		jsonResponse, err = filterFields(r, jsonResponse)
+	}
+
+	if err == nil && Envelope() == EnvelopeV2 {
+		jsonResponse, err = wrapEnvelope(httpStatus, jsonResponse)
+	}
+
+	if err != nil {
+
+		log.Println("jsonResponse Error: " + err.Error())
+
+		httpStatus = http.StatusInternalServerError
+
+		jsonResponse, err = json.Marshal(&interfaces.IDefaultResponse{
+			Status:  httpStatus,
+			Message: "Error 500, the response could not be encoded",
+		})
+
+		if err == nil && Envelope() == EnvelopeV2 {
+			jsonResponse, err = wrapEnvelope(httpStatus, jsonResponse)
+		}
+
+		if err != nil && Envelope() == EnvelopeV2 {
+			jsonResponse = internalServerErrorV2
+		} else if err != nil {
+			jsonResponse = internalServerError
+		}
+
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	w.Write(jsonResponse)
+
+}
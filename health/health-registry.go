@@ -0,0 +1,171 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/m4r4v/go-rest-api/clock"
+)
+
+// HealthCheck reports an error when the checked dependency is not ready
+type HealthCheck func(ctx context.Context) error
+
+type HealthRegistry struct {
+	// mu guards the fields below and is never held while checks run, runMu
+	// lets a single Check run the checks while concurrent ones wait for its
+	// cached result
+	mu    sync.Mutex
+	runMu sync.Mutex
+
+	checks   map[string]HealthCheck
+	timeout  time.Duration
+	cacheTTL time.Duration
+	cached   map[string]error
+	cachedAt time.Time
+
+	// incremented by Register so a run that started before it is not cached
+	generation int
+}
+
+var errHealthCheckTimeout = errors.New("health check timed out")
+
+// default registry used by /readyz
+var Registry = NewHealthRegistry(2*time.Second, 5*time.Second)
+
+// NewHealthRegistry creates a registry where every check gets its own timeout
+// and the aggregated result is reused for cacheTTL
+func NewHealthRegistry(timeout, cacheTTL time.Duration) *HealthRegistry {
+	return &HealthRegistry{
+		checks:   map[string]HealthCheck{},
+		timeout:  timeout,
+		cacheTTL: cacheTTL,
+	}
+}
+
+// Register adds a named check to the default registry
+func Register(name string, check HealthCheck) {
+	Registry.Register(name, check)
+}
+
+// Register adds or replaces a named check
+func (h *HealthRegistry) Register(name string, check HealthCheck) {
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checks[name] = check
+
+	// invalidate cached results so the new check runs on the next request
+	h.cached = nil
+	h.generation++
+
+}
+
+// Names returns the registered check names sorted alphabetically
+func (h *HealthRegistry) Names() []string {
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	names := make([]string, 0, len(h.checks))
+
+	for name := range h.checks {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+
+}
+
+// Check runs every registered check concurrently and returns the error of each
+// one by name, a nil error means the check passed. Checks run detached from
+// any request so a client that disconnects can not fail them, and the cached
+// result only ever reflects the checks themselves. Register and Names do not
+// wait for running checks
+func (h *HealthRegistry) Check() map[string]error {
+
+	h.runMu.Lock()
+	defer h.runMu.Unlock()
+
+	h.mu.Lock()
+
+	if h.cached != nil && clock.Since(h.cachedAt) < h.cacheTTL {
+		cached := h.cached
+		h.mu.Unlock()
+		return cached
+	}
+
+	checks := make(map[string]HealthCheck, len(h.checks))
+
+	for name, check := range h.checks {
+		checks[name] = check
+	}
+
+	generation := h.generation
+
+	h.mu.Unlock()
+
+	results := make(map[string]error, len(checks))
+
+	var wg sync.WaitGroup
+	var resultsMu sync.Mutex
+
+	for name, check := range checks {
+
+		wg.Add(1)
+
+		go func(name string, check HealthCheck) {
+
+			defer wg.Done()
+
+			err := h.run(check)
+
+			resultsMu.Lock()
+			results[name] = err
+			resultsMu.Unlock()
+
+		}(name, check)
+
+	}
+
+	wg.Wait()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// checks registered meanwhile run on the next request
+	if h.generation == generation {
+		h.cached = results
+		h.cachedAt = clock.Now()
+	}
+
+	return results
+
+}
+
+// run executes a single check, giving up when its timeout expires even if the
+// check itself ignores the context
+func (h *HealthRegistry) run(check HealthCheck) error {
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return errHealthCheckTimeout
+	}
+
+}
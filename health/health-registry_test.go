@@ -0,0 +1,134 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/m4r4v/go-rest-api/clock"
+)
+
+func TestCheckTimesOutOnlyTheSlowCheck(t *testing.T) {
+
+	registry := NewHealthRegistry(50*time.Millisecond, time.Minute)
+
+	registry.Register("fast", func(ctx context.Context) error {
+		return nil
+	})
+
+	registry.Register("slow", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	results := registry.Check()
+
+	if results["fast"] != nil {
+		t.Errorf("fast check failed: %v", results["fast"])
+	}
+
+	if results["slow"] != errHealthCheckTimeout {
+		t.Errorf("slow check = %v, want %v", results["slow"], errHealthCheckTimeout)
+	}
+
+}
+
+func TestCheckGetsAContextThatIsNotCancelled(t *testing.T) {
+
+	registry := NewHealthRegistry(time.Second, time.Minute)
+
+	registry.Register("context", func(ctx context.Context) error {
+		return ctx.Err()
+	})
+
+	if err := registry.Check()["context"]; err != nil {
+		t.Errorf("check saw context error %v", err)
+	}
+
+}
+
+func TestCheckCacheExpiresWithTheClock(t *testing.T) {
+
+	frozen := clock.NewFrozenClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	t.Cleanup(clock.Set(frozen))
+
+	registry := NewHealthRegistry(time.Second, 5*time.Second)
+
+	calls := 0
+
+	registry.Register("counted", func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+
+	registry.Check()
+
+	frozen.Advance(4 * time.Second)
+	registry.Check()
+
+	if calls != 1 {
+		t.Fatalf("check ran %d times within the cache TTL, want 1", calls)
+	}
+
+	frozen.Advance(time.Second)
+	registry.Check()
+
+	if calls != 2 {
+		t.Fatalf("check ran %d times after the cache TTL, want 2", calls)
+	}
+
+}
+
+func TestRegistryIsUsableWhileChecksRun(t *testing.T) {
+
+	registry := NewHealthRegistry(time.Minute, time.Minute)
+
+	started, release := make(chan struct{}, 1), make(chan struct{})
+
+	registry.Register("blocked", func(ctx context.Context) error {
+
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+
+		<-release
+
+		return nil
+
+	})
+
+	done := make(chan map[string]error)
+
+	go func() { done <- registry.Check() }()
+
+	<-started
+
+	names := make(chan []string)
+
+	go func() {
+		registry.Register("late", func(ctx context.Context) error { return nil })
+		names <- registry.Names()
+	}()
+
+	select {
+	case got := <-names:
+		if len(got) != 2 {
+			t.Errorf("Names() = %v, want both checks", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Register and Names waited for the running check")
+	}
+
+	close(release)
+
+	if results := <-done; len(results) != 1 {
+		t.Errorf("running Check = %v, want only the check registered before it", results)
+	}
+
+	// the check registered during the run invalidated its result
+	if results := registry.Check(); len(results) != 2 {
+		t.Errorf("next Check = %v, want both checks", results)
+	}
+
+}
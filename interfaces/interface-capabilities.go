@@ -0,0 +1,15 @@
+package interfaces
+
+type ICapabilities struct {
+	Status          int      `json:"status-code"`
+	APIVersions     []string `json:"api-versions"`
+	Envelope        string   `json:"response-envelope"`
+	RequestSigning  bool     `json:"request-signing"`
+	Deprecations    bool     `json:"route-deprecations"`
+	ConcurrencyCaps bool     `json:"route-concurrency-limits"`
+	AdminListener   bool     `json:"admin-listener"`
+	RateLimiting    bool     `json:"rate-limiting"`
+	FieldFiltering  bool     `json:"field-filtering"`
+	HealthChecks    []string `json:"health-checks"`
+	Storage         string   `json:"storage"`
+}
@@ -0,0 +1,9 @@
+package interfaces
+
+type IConnectionMetrics struct {
+	Status   int   `json:"status-code"`
+	Accepted int64 `json:"accepted"`
+	Rejected int64 `json:"rejected"`
+	Active   int64 `json:"active"`
+	Idle     int64 `json:"idle"`
+}
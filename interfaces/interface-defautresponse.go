@@ -1,6 +1,7 @@
 package interfaces
 
 type IDefaultResponse struct {
-	Status  int    `json:"status-code"`
-	Message string `json:"message"`
+	Status  int                    `json:"status-code"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details,omitempty"`
 }
@@ -0,0 +1,6 @@
+package interfaces
+
+type IDeprecationMetrics struct {
+	Status int              `json:"status-code"`
+	Calls  map[string]int64 `json:"calls"`
+}
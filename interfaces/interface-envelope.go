@@ -0,0 +1,21 @@
+package interfaces
+
+type IAppInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type IEnvelopeError struct {
+	Message interface{} `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// IEnvelope is the "v2" response envelope, the payload goes in Data on
+// success and its message and details in Error otherwise
+type IEnvelope struct {
+	Success bool            `json:"success"`
+	Status  int             `json:"status"`
+	Data    interface{}     `json:"data,omitempty"`
+	Error   *IEnvelopeError `json:"error,omitempty"`
+	App     IAppInfo        `json:"app"`
+}
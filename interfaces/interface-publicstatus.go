@@ -0,0 +1,9 @@
+package interfaces
+
+type IPublicStatus struct {
+	Status  int    `json:"status-code"`
+	Message string `json:"message"`
+	Health  string `json:"health"`
+	Version string `json:"version"`
+	Notice  string `json:"notice,omitempty"`
+}
@@ -0,0 +1,7 @@
+package interfaces
+
+type IReadyResponse struct {
+	Status  int               `json:"status-code"`
+	Message string            `json:"message"`
+	Checks  map[string]string `json:"checks"`
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+type ISelfTestStep struct {
+	Name     string `json:"name"`
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	Expected int    `json:"expected"`
+	Actual   int    `json:"actual"`
+	Passed   bool   `json:"passed"`
+	Error    string `json:"error,omitempty"`
+}
+
+type ISelfTestReport struct {
+	Passed bool             `json:"passed"`
+	Steps  []*ISelfTestStep `json:"steps"`
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+type IVersionResponse struct {
+	Status      int      `json:"status-code"`
+	Version     string   `json:"version"`
+	GitCommit   string   `json:"git-commit"`
+	BuildDate   string   `json:"build-date"`
+	GoVersion   string   `json:"go-version"`
+	APIVersions []string `json:"api-versions"`
+}
@@ -1,9 +1,28 @@
 package main
 
 import (
+	"flag"
+	"os"
+
 	api "github.com/m4r4v/go-rest-api/api"
 )
 
 func main() {
+
+	selfTest := flag.Bool("self-test", false, "run the smoke flow against an in-memory server and exit")
+
+	flag.Parse()
+
+	if *selfTest {
+
+		if !api.SelfTest(os.Stdout) {
+			os.Exit(1)
+		}
+
+		return
+
+	}
+
 	api.ServerRouter()
+
 }
@@ -0,0 +1,83 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/m4r4v/go-rest-api/handlers"
+	"github.com/m4r4v/go-rest-api/interfaces"
+)
+
+// Redoc release loaded by the documentation page
+const RedocScript = "https://cdn.jsdelivr.net/npm/redoc@2.1.5/bundles/redoc.standalone.js"
+
+// Redoc page rendering /openapi.json, the script is only loaded with its
+// Subresource Integrity hash so a tampered CDN file is never run, without one
+// the page links to the document instead
+var docsPage = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html>
+<head>
+	<title>API documentation</title>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+</head>
+<body>
+{{- if .Integrity}}
+	<redoc spec-url="openapi.json"></redoc>
+	<script src="{{.Script}}" integrity="{{.Integrity}}" crossorigin="anonymous"></script>
+{{- else}}
+	<p>The OpenAPI document is served at <a href="openapi.json">openapi.json</a>.</p>
+{{- end}}
+</body>
+</html>
+`))
+
+// Handler serves the document of router, built on every request so routes
+// added after startup are included. The spec is written as is, outside of the
+// response envelope, so OpenAPI tooling can read it
+func Handler(router *mux.Router, title, version string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		jsonResponse, err := json.Marshal(Build(router, title, version))
+
+		if err != nil {
+
+			log.Println("OpenAPI Error: " + err.Error())
+
+			handlers.WriteJSON(w, r, http.StatusInternalServerError, &interfaces.IDefaultResponse{
+				Status:  http.StatusInternalServerError,
+				Message: "Error 500, the OpenAPI document could not be encoded",
+			})
+
+			return
+
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(jsonResponse)
+
+	}
+}
+
+// DocsHandler serves the Redoc page, integrity is the Subresource Integrity
+// hash of RedocScript, such as "sha384-..."
+func DocsHandler(integrity string) http.HandlerFunc {
+
+	page := new(bytes.Buffer)
+
+	docsPage.Execute(page, struct{ Script, Integrity string }{RedocScript, integrity})
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write(page.Bytes())
+
+	}
+
+}
@@ -0,0 +1,95 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// schemaName names the component of a response or request type,
+// interfaces.IReadyResponse is documented as ReadyResponse
+func schemaName(t reflect.Type) string {
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	name := t.Name()
+
+	if len(name) > 1 && name[0] == 'I' && unicode.IsUpper(rune(name[1])) {
+		name = name[1:]
+	}
+
+	return name
+
+}
+
+// schemaOf describes t the way encoding/json writes it, no property is
+// required since ?fields= may leave any of them out
+func schemaOf(t reflect.Type) Schema {
+
+	switch t.Kind() {
+
+	case reflect.Ptr:
+		return schemaOf(t.Elem())
+
+	case reflect.Bool:
+		return Schema{Type: "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{Type: "integer"}
+
+	case reflect.Float32, reflect.Float64:
+		return Schema{Type: "number"}
+
+	case reflect.String:
+		return Schema{Type: "string"}
+
+	case reflect.Slice, reflect.Array:
+		items := schemaOf(t.Elem())
+		return Schema{Type: "array", Items: &items}
+
+	case reflect.Map:
+		values := schemaOf(t.Elem())
+		return Schema{Type: "object", AdditionalProperties: &values}
+
+	case reflect.Struct:
+
+		if t == reflect.TypeOf(time.Time{}) {
+			return Schema{Type: "string", Format: "date-time"}
+		}
+
+		schema := Schema{Type: "object", Properties: map[string]Schema{}}
+
+		for i := 0; i < t.NumField(); i++ {
+
+			field := t.Field(i)
+
+			if !field.IsExported() {
+				continue
+			}
+
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+			if name == "-" {
+				continue
+			}
+
+			if name == "" {
+				name = field.Name
+			}
+
+			schema.Properties[name] = schemaOf(field.Type)
+
+		}
+
+		return schema
+
+	}
+
+	// interface{} holds any value
+	return Schema{}
+
+}
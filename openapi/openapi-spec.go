@@ -0,0 +1,350 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/m4r4v/go-rest-api/handlers"
+	"github.com/m4r4v/go-rest-api/interfaces"
+)
+
+// gorilla/mux variables may carry a pattern, {id:[0-9]+}, OpenAPI only wants {id}
+var variablePattern = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Paths      map[string]map[string]Operation `json:"paths"`
+	Components Components                      `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+	Security    []map[string][]any  `json:"security,omitempty"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Parameter struct {
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required"`
+	Schema   Schema `json:"schema"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema Schema `json:"schema"`
+}
+
+type Schema struct {
+	Type                 string            `json:"type,omitempty"`
+	Format               string            `json:"format,omitempty"`
+	Ref                  string            `json:"$ref,omitempty"`
+	Properties           map[string]Schema `json:"properties,omitempty"`
+	Required             []string          `json:"required,omitempty"`
+	Items                *Schema           `json:"items,omitempty"`
+	AdditionalProperties *Schema           `json:"additionalProperties,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]Schema         `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+}
+
+// how a route is documented, routes serving several methods are registered
+// once per method so each method has its own entry
+type routeDoc struct {
+	secured   bool
+	hidden    bool
+	request   reflect.Type
+	responses map[int]reflect.Type
+}
+
+var docs = struct {
+	sync.RWMutex
+	routes map[*mux.Route]*routeDoc
+}{routes: map[*mux.Route]*routeDoc{}}
+
+// describe applies change to the documentation of route and returns route
+func describe(route *mux.Route, change func(doc *routeDoc)) *mux.Route {
+
+	docs.Lock()
+	defer docs.Unlock()
+
+	doc := docs.routes[route]
+
+	if doc == nil {
+		doc = &routeDoc{responses: map[int]reflect.Type{}}
+		docs.routes[route] = doc
+	}
+
+	change(doc)
+
+	return route
+
+}
+
+func routeDocOf(route *mux.Route) routeDoc {
+
+	docs.RLock()
+	defer docs.RUnlock()
+
+	if doc := docs.routes[route]; doc != nil {
+		return *doc
+	}
+
+	return routeDoc{}
+
+}
+
+// Secured documents route as requiring a bearer token and returns it
+func Secured(route *mux.Route) *mux.Route {
+	return describe(route, func(doc *routeDoc) { doc.secured = true })
+}
+
+// Hidden leaves route out of the document, for routes that do not answer in
+// JSON such as the documentation page itself
+func Hidden(route *mux.Route) *mux.Route {
+	return describe(route, func(doc *routeDoc) { doc.hidden = true })
+}
+
+// Accepts documents v as the JSON request body of route
+func Accepts(route *mux.Route, v interface{}) *mux.Route {
+	return describe(route, func(doc *routeDoc) { doc.request = reflect.TypeOf(v) })
+}
+
+// Returns documents v as the payload route answers with for every status,
+// 200 when none is given. Statuses not listed, and routes never described,
+// answer with interfaces.IDefaultResponse
+func Returns(route *mux.Route, v interface{}, statuses ...int) *mux.Route {
+
+	if len(statuses) == 0 {
+		statuses = []int{http.StatusOK}
+	}
+
+	return describe(route, func(doc *routeDoc) {
+		for _, status := range statuses {
+			doc.responses[status] = reflect.TypeOf(v)
+		}
+	})
+
+}
+
+// Build describes every route registered on router that is not Hidden,
+// payloads are documented inside the envelope selected with
+// handlers.SetEnvelope
+func Build(router *mux.Router, title, version string) *Document {
+
+	document := &Document{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: title, Version: version},
+		Paths:   map[string]map[string]Operation{},
+		Components: Components{
+			Schemas: map[string]Schema{},
+			SecuritySchemes: map[string]SecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer"},
+			},
+		},
+	}
+
+	router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+
+		template, err := route.GetPathTemplate()
+
+		if err != nil || route.GetHandler() == nil {
+			return nil
+		}
+
+		doc := routeDocOf(route)
+
+		if doc.hidden {
+			return nil
+		}
+
+		methods, err := route.GetMethods()
+
+		if err != nil {
+			methods = []string{"GET"}
+		}
+
+		path := variablePattern.ReplaceAllString(template, "{$1}")
+
+		if document.Paths[path] == nil {
+			document.Paths[path] = map[string]Operation{}
+		}
+
+		for _, method := range methods {
+			document.Paths[path][strings.ToLower(method)] = document.operation(method, path, doc)
+		}
+
+		return nil
+
+	})
+
+	return document
+
+}
+
+// component adds the schema of t to the document and returns a reference to it
+func (document *Document) component(t reflect.Type) Schema {
+
+	name := schemaName(t)
+
+	if _, ok := document.Components.Schemas[name]; !ok {
+		document.Components.Schemas[name] = schemaOf(t)
+	}
+
+	return Schema{Ref: "#/components/schemas/" + name}
+
+}
+
+// envelope wraps the payload schema of a response with status in the active
+// envelope, v2 keeps successful payloads in data and the fields of errors,
+// other than the message, in error.details
+func (document *Document) envelope(status int, payload Schema) Schema {
+
+	if handlers.Envelope() != handlers.EnvelopeV2 {
+		return payload
+	}
+
+	app := document.component(reflect.TypeOf(interfaces.IAppInfo{}))
+
+	if status < http.StatusBadRequest {
+		return Schema{
+			Type: "object",
+			Properties: map[string]Schema{
+				"success": {Type: "boolean"},
+				"status":  {Type: "integer"},
+				"data":    payload,
+				"app":     app,
+			},
+			Required: []string{"success", "status", "data", "app"},
+		}
+	}
+
+	return Schema{
+		Type: "object",
+		Properties: map[string]Schema{
+			"success": {Type: "boolean"},
+			"status":  {Type: "integer"},
+			"error": {
+				Type: "object",
+				Properties: map[string]Schema{
+					"message": {Type: "string"},
+					"details": {Type: "object", AdditionalProperties: &Schema{}},
+				},
+				Required: []string{"message"},
+			},
+			"app": app,
+		},
+		Required: []string{"success", "status", "error", "app"},
+	}
+
+}
+
+func (document *Document) operation(method, path string, doc routeDoc) Operation {
+
+	defaultResponse := reflect.TypeOf(interfaces.IDefaultResponse{})
+
+	op := Operation{
+		OperationID: operationID(method, path),
+		Responses: map[string]Response{
+			"default": {
+				Description: "Error",
+				Content: map[string]MediaType{
+					"application/json": {Schema: document.envelope(http.StatusInternalServerError, document.component(defaultResponse))},
+				},
+			},
+		},
+	}
+
+	responses := doc.responses
+
+	if len(responses) == 0 {
+		responses = map[int]reflect.Type{http.StatusOK: defaultResponse}
+	}
+
+	for status, payload := range responses {
+		op.Responses[strconv.Itoa(status)] = Response{
+			Description: http.StatusText(status),
+			Content: map[string]MediaType{
+				"application/json": {Schema: document.envelope(status, document.component(payload))},
+			},
+		}
+	}
+
+	if doc.request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]MediaType{
+				"application/json": {Schema: document.component(doc.request)},
+			},
+		}
+	}
+
+	for _, match := range variablePattern.FindAllStringSubmatch(path, -1) {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   Schema{Type: "string"},
+		})
+	}
+
+	sort.Slice(op.Parameters, func(i, j int) bool {
+		return op.Parameters[i].Name < op.Parameters[j].Name
+	})
+
+	if doc.secured {
+		op.Security = []map[string][]any{{"bearerAuth": {}}}
+	}
+
+	return op
+
+}
+
+// operationID turns GET /v1/users/{id} into get_v1_users_id
+func operationID(method, path string) string {
+
+	id := strings.ToLower(method)
+
+	for _, segment := range strings.Split(path, "/") {
+
+		segment = strings.Trim(segment, "{}")
+
+		if segment != "" {
+			id += "_" + segment
+		}
+
+	}
+
+	return id
+
+}
@@ -0,0 +1,138 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/m4r4v/go-rest-api/handlers"
+	"github.com/m4r4v/go-rest-api/interfaces"
+)
+
+func noop(w http.ResponseWriter, r *http.Request) {}
+
+type motdBody struct {
+	Message string `json:"message"`
+}
+
+func testRouter() *mux.Router {
+
+	router := mux.NewRouter()
+
+	Hidden(router.HandleFunc("/docs", noop).Methods("GET"))
+	Returns(router.HandleFunc("/readyz", noop).Methods("GET"), interfaces.IReadyResponse{}, http.StatusOK, http.StatusServiceUnavailable)
+	router.HandleFunc("/motd", noop).Methods("GET")
+	Accepts(Secured(router.HandleFunc("/motd", noop).Methods("PUT")), motdBody{})
+
+	return router
+
+}
+
+func TestBuild(t *testing.T) {
+
+	document := Build(testRouter(), "test", "1.0.0")
+
+	if _, ok := document.Paths["/docs"]; ok {
+		t.Error("hidden route /docs is documented")
+	}
+
+	readyz := document.Paths["/readyz"]["get"]
+
+	for _, status := range []string{"200", "503"} {
+		if ref := readyz.Responses[status].Content["application/json"].Schema.Ref; ref != "#/components/schemas/ReadyResponse" {
+			t.Errorf("/readyz %s schema %q, want ReadyResponse", status, ref)
+		}
+	}
+
+	checks := document.Components.Schemas["ReadyResponse"].Properties["checks"]
+
+	if checks.Type != "object" || checks.AdditionalProperties == nil || checks.AdditionalProperties.Type != "string" {
+		t.Errorf("checks schema %+v, want a map of strings", checks)
+	}
+
+	get, put := document.Paths["/motd"]["get"], document.Paths["/motd"]["put"]
+
+	if get.RequestBody != nil || get.Security != nil {
+		t.Errorf("GET /motd %+v, want no body and no security", get)
+	}
+
+	if put.RequestBody == nil || put.RequestBody.Content["application/json"].Schema.Ref != "#/components/schemas/motdBody" || put.Security == nil {
+		t.Errorf("PUT /motd %+v, want a secured motdBody request", put)
+	}
+
+	if ref := get.Responses["200"].Content["application/json"].Schema.Ref; ref != "#/components/schemas/DefaultResponse" {
+		t.Errorf("GET /motd schema %q, want DefaultResponse", ref)
+	}
+
+}
+
+func TestBuildEnvelopeV2(t *testing.T) {
+
+	previous := handlers.Envelope()
+	handlers.SetEnvelope(handlers.EnvelopeV2)
+	t.Cleanup(func() { handlers.SetEnvelope(previous) })
+
+	readyz := Build(testRouter(), "test", "1.0.0").Paths["/readyz"]["get"]
+
+	ok := readyz.Responses["200"].Content["application/json"].Schema
+
+	if ok.Properties["data"].Ref != "#/components/schemas/ReadyResponse" {
+		t.Errorf("200 schema %+v, want the payload in data", ok)
+	}
+
+	failed := readyz.Responses["503"].Content["application/json"].Schema
+
+	if _, ok := failed.Properties["error"].Properties["details"]; !ok {
+		t.Errorf("503 schema %+v, want an error with details", failed)
+	}
+
+}
+
+func TestSchemaOf(t *testing.T) {
+
+	got := schemaOf(reflect.TypeOf(interfaces.IDefaultResponse{}))
+
+	want := Schema{
+		Type: "object",
+		Properties: map[string]Schema{
+			"status-code": {Type: "integer"},
+			"message":     {Type: "string"},
+			"details":     {Type: "object", AdditionalProperties: &Schema{}},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("schemaOf(IDefaultResponse) = %+v, want %+v", got, want)
+	}
+
+}
+
+func TestDocsHandler(t *testing.T) {
+
+	tests := []struct {
+		integrity string
+		want      string
+	}{
+		{"sha384-abc", `<script src="` + RedocScript + `" integrity="sha384-abc" crossorigin="anonymous">`},
+		{"", `<a href="openapi.json">`},
+	}
+
+	for _, test := range tests {
+
+		w := httptest.NewRecorder()
+		DocsHandler(test.integrity)(w, httptest.NewRequest("GET", "/docs", nil))
+
+		if body := w.Body.String(); !strings.Contains(body, test.want) {
+			t.Errorf("integrity %q: page %s, want %s", test.integrity, body, test.want)
+		}
+
+		if test.integrity == "" && strings.Contains(w.Body.String(), "<script") {
+			t.Error("page without integrity loads a script")
+		}
+
+	}
+
+}
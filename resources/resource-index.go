@@ -1,27 +1,26 @@
 package resources
 
 import (
-	"encoding/json"
-	"log"
 	"net/http"
 
 	auth "github.com/m4r4v/go-rest-api/auth"
+	handlers "github.com/m4r4v/go-rest-api/handlers"
 	interfaces "github.com/m4r4v/go-rest-api/interfaces"
 )
 
-var response *interfaces.IDefaultResponse
-
 func ResourceIndex(w http.ResponseWriter, r *http.Request) {
 
+	var response *interfaces.IDefaultResponse
+
 	// check if user is authorized or authenticated
-	if !auth.AuthorizationBearerToken(r.Header.Get("Authorization")) {
+	if !auth.AuthorizationRequest(r) {
 
 		response = &interfaces.IDefaultResponse{
 			Status:  http.StatusForbidden,
 			Message: "Error 403, you do no have permission to access this resource",
 		}
 
-		log.Println("Index Forbidden")
+		handlers.Log(r, "Index Forbidden")
 
 	} else {
 
@@ -30,18 +29,10 @@ func ResourceIndex(w http.ResponseWriter, r *http.Request) {
 			Message: "Hello world!",
 		}
 
-		log.Println("Index")
-
-	}
-
-	jsonResponse, err := json.Marshal(response)
+		handlers.Log(r, "Index")
 
-	if err != nil {
-		log.Fatal("jsonResponse Error: " + err.Error())
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.Status)
-	w.Write(jsonResponse)
+	handlers.WriteJSON(w, r, response.Status, response)
 
 }
@@ -0,0 +1,88 @@
+package resources
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	auth "github.com/m4r4v/go-rest-api/auth"
+	handlers "github.com/m4r4v/go-rest-api/handlers"
+	interfaces "github.com/m4r4v/go-rest-api/interfaces"
+)
+
+type MotdData struct {
+	Message string `json:"message"`
+}
+
+// message of the day, shared by every request
+var motd = struct {
+	sync.RWMutex
+	message string
+}{}
+
+// Motd returns the current message of the day, empty if none was set
+func Motd() string {
+
+	motd.RLock()
+	defer motd.RUnlock()
+
+	return motd.message
+
+}
+
+func ResourceMotd(w http.ResponseWriter, r *http.Request) {
+
+	var responseMotd *interfaces.IDefaultResponse
+
+	switch r.Method {
+
+	case http.MethodPut:
+
+		// only authorized users can change the message of the day
+		if !auth.AuthorizationRequest(r) {
+
+			responseMotd = &interfaces.IDefaultResponse{
+				Status:  http.StatusForbidden,
+				Message: "Error 403, you do no have permission to access this resource",
+			}
+
+			handlers.Log(r, "Motd Forbidden")
+
+			break
+
+		}
+
+		var post MotdData
+
+		err := json.NewDecoder(r.Body).Decode(&post)
+
+		// body too large or not JSON
+		if err != nil {
+			handlers.WriteBodyError(w, r, err)
+			return
+		}
+
+		motd.Lock()
+		motd.message = strings.TrimSpace(post.Message)
+		motd.Unlock()
+
+		responseMotd = &interfaces.IDefaultResponse{
+			Status:  http.StatusOK,
+			Message: Motd(),
+		}
+
+		handlers.Log(r, "Motd Updated")
+
+	default:
+
+		responseMotd = &interfaces.IDefaultResponse{
+			Status:  http.StatusOK,
+			Message: Motd(),
+		}
+
+	}
+
+	handlers.WriteJSON(w, r, responseMotd.Status, responseMotd)
+
+}
@@ -0,0 +1,61 @@
+package resources
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	clock "github.com/m4r4v/go-rest-api/clock"
+	handlers "github.com/m4r4v/go-rest-api/handlers"
+	health "github.com/m4r4v/go-rest-api/health"
+	interfaces "github.com/m4r4v/go-rest-api/interfaces"
+)
+
+// how long a computed public status is served before running the checks again
+const publicStatusTTL = 10 * time.Second
+
+var publicStatus = struct {
+	sync.Mutex
+	response *interfaces.IPublicStatus
+	cachedAt time.Time
+}{}
+
+// ResourcePublicStatus returns the unauthenticated status handler, the
+// response is cached so bursts of traffic never reach the health checks
+func ResourcePublicStatus(version string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		publicStatus.Lock()
+
+		if publicStatus.response == nil || clock.Since(publicStatus.cachedAt) > publicStatusTTL {
+
+			publicStatus.response = &interfaces.IPublicStatus{
+				Status:  http.StatusOK,
+				Message: "Status",
+				Health:  "ok",
+				Version: version,
+				Notice:  Motd(),
+			}
+
+			for _, err := range health.Registry.Check() {
+				if err != nil {
+					publicStatus.response.Health = "degraded"
+					break
+				}
+			}
+
+			publicStatus.cachedAt = clock.Now()
+
+		}
+
+		response := publicStatus.response
+
+		publicStatus.Unlock()
+
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(publicStatusTTL.Seconds())))
+
+		handlers.WriteJSON(w, r, response.Status, response)
+
+	}
+}
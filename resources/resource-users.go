@@ -2,10 +2,11 @@ package resources
 
 import (
 	"encoding/json"
-	"log"
 	"net/http"
+	"strings"
 
 	auth "github.com/m4r4v/go-rest-api/auth"
+	handlers "github.com/m4r4v/go-rest-api/handlers"
 	interfaces "github.com/m4r4v/go-rest-api/interfaces"
 )
 
@@ -14,19 +15,25 @@ type PostData struct {
 	Password string `json:"password"`
 }
 
-var responseUsers *interfaces.IDefaultResponse
+// NormalizeUsername trims and lowercases a username or email so lookups and
+// comparisons never depend on the case the client used
+func NormalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
 
 func ResourceUsers(w http.ResponseWriter, r *http.Request) {
 
+	var responseUsers *interfaces.IDefaultResponse
+
 	// check if user is authorized or authenticated
-	if !auth.AuthorizationBearerToken(r.Header.Get("Authorization")) {
+	if !auth.AuthorizationRequest(r) {
 
 		responseUsers = &interfaces.IDefaultResponse{
 			Status:  http.StatusForbidden,
 			Message: "Error 403, you do no have permission to access this resource",
 		}
 
-		log.Println("Index Forbidden")
+		handlers.Log(r, "Index Forbidden")
 
 	} else {
 
@@ -35,34 +42,35 @@ func ResourceUsers(w http.ResponseWriter, r *http.Request) {
 		var post PostData
 		err := decoder.Decode(&post)
 
+		// body too large or not JSON
 		if err != nil {
-			panic(err)
+			handlers.WriteBodyError(w, r, err)
+			return
 		}
 
+		// usernames are case insensitive, "Nano@Gmail.com" is "nano@gmail.com"
+		post.Username = NormalizeUsername(post.Username)
+
 		if post.Username != "nano@gmail.com" {
+
 			responseUsers = &interfaces.IDefaultResponse{
 				Status:  http.StatusForbidden,
 				Message: "Tu nombre de usuario es erroneo",
 			}
-		}
 
-		responseUsers = &interfaces.IDefaultResponse{
-			Status:  http.StatusOK,
-			Message: "username: " + post.Username + ", password: " + post.Password,
-		}
+		} else {
 
-		log.Println("username: " + post.Username + ", password: " + post.Password)
+			responseUsers = &interfaces.IDefaultResponse{
+				Status:  http.StatusOK,
+				Message: "username: " + post.Username + ", password: " + post.Password,
+			}
 
-	}
+			handlers.Log(r, "username: "+post.Username+", password: "+post.Password)
 
-	jsonResponse, err := json.Marshal(responseUsers)
+		}
 
-	if err != nil {
-		log.Fatal("jsonResponse Error: " + err.Error())
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(responseUsers.Status)
-	w.Write(jsonResponse)
+	handlers.WriteJSON(w, r, responseUsers.Status, responseUsers)
 
 }
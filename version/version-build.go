@@ -0,0 +1,12 @@
+package version
+
+// build metadata, set at build time with
+//
+//	go build -ldflags "-X github.com/m4r4v/go-rest-api/version.Version=1.2.3 \
+//		-X github.com/m4r4v/go-rest-api/version.GitCommit=$(git rev-parse --short HEAD) \
+//		-X github.com/m4r4v/go-rest-api/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Version   = "dev"
+	GitCommit = "unknown"
+	BuildDate = "unknown"
+)